@@ -0,0 +1,107 @@
+// Command import creates a draft session from a JSON, YAML, CSV or Markdown definition.
+//
+//	go run ./cmd/import -organizer 66f0... retro.md
+//	go run ./cmd/import -dry-run -format csv -title "Sprint 42" < questions.csv
+package main
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/importer"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func main() {
+	format := flag.String("format", "", "json, yaml, csv or markdown (default: from the file extension)");
+	title := flag.String("title", "", "title for documents without one, e.g. CSV files");
+	organizer := flag.String("organizer", "", "id of the organizer owning the session");
+	dryRun := flag.Bool("dry-run", false, "only validate, do not save");
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: import [flags] <file | ->\n");
+		flag.PrintDefaults();
+	}
+	flag.Parse();
+
+	if flag.NArg() != 1 {
+		flag.Usage();
+		os.Exit(2);
+	}
+	path := flag.Arg(0);
+
+	var reader io.Reader = os.Stdin;
+	if path != "-" {
+		file, err := os.Open(path);
+		if err != nil {
+			log.Fatal(err);
+		}
+		defer file.Close();
+		reader = file;
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, importer.MaxDocumentBytes+1));
+	if err != nil {
+		log.Fatal(err);
+	}
+
+	if *format == "" {
+		*format = importer.DetectFormat(path, "");
+	}
+	if *format == "" {
+		log.Fatal("cannot tell the format from the file name, pass -format");
+	}
+
+	session, err := importer.Parse(*format, data, *title);
+	if err != nil {
+		var importError *importer.Error;
+		if errors.As(err, &importError) {
+			for _, lineError := range importError.Errors {
+				if lineError.Line > 0 {
+					fmt.Fprintf(os.Stderr, "%s:%d: %s\n", path, lineError.Line, lineError.Message);
+				} else {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", path, lineError.Message);
+				}
+			}
+			os.Exit(1);
+		}
+		log.Fatal(err);
+	}
+
+	if *organizer != "" {
+		session.OrganizerId, err = primitive.ObjectIDFromHex(*organizer);
+		if err != nil {
+			log.Fatal("invalid organizer id: ", err);
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("%s: valid, %q with %d questions\n", path, session.Title, len(session.Questions));
+		return;
+	}
+
+	mongoInstance := database.GetMongoInstance();
+	if err := mongoInstance.Init(utils.MONGO_CONNECTION, utils.DB_NAME); err != nil {
+		log.Fatal("MongoDB init failed:", err);
+	}
+	defer mongoInstance.Close();
+
+	// the session is cached like one created through the API.
+	redisInstance := database.GetRedisInstance();
+	if err := redisInstance.Init(utils.REDIS_CONNECTION); err != nil {
+		log.Fatal("Redis init failed : ", err);
+	}
+	defer redisInstance.Close();
+
+	sessionID, err := services.SavePollQuestions(*session);
+	if err != nil {
+		log.Fatal(err);
+	}
+	fmt.Printf("session %s imported as %s, join code %s\n", *sessionID, session.Status, session.JoinCode);
+}
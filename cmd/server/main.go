@@ -6,6 +6,7 @@ import (
 	"RealTimePoll/internal/realtime"
 	"RealTimePoll/internal/routers"
 	"RealTimePoll/internal/utils"
+	"RealTimePoll/pkg/receipt"
 	"log"
 	"net/http"
 
@@ -18,7 +19,15 @@ func setUpConfig() {
 }
 
 func main() {
-	
+
+	// secrets without a safe default: refuse to start rather than sign and hash with a known key.
+	if err := utils.LoadBallotHashKey(); err != nil {
+		log.Fatal("Secret ballot key missing: ", err);
+	}
+	if err := receipt.Init(); err != nil {
+		log.Fatal("Receipt signing key missing: ", err);
+	}
+
 	mongoInstance := database.GetMongoInstance();
 	if err := mongoInstance.Init(utils.MONGO_CONNECTION, utils.DB_NAME);err != nil {
 		log.Fatal("MongoDB init failed:", err);
@@ -89,6 +89,22 @@ func (m *mongoDB) GetClient() *mongo.Client {
 }
 
 
+// WithTransaction runs fn in a multi-document transaction, retried by the driver on transient
+// errors. Transactions need MongoDB running as a replica set (Atlas always is).
+func (m *mongoDB) WithTransaction(ctx context.Context, fn func(ctx mongo.SessionContext) error) error {
+	session, err := m.GetClient().StartSession();
+	if err != nil {
+		return fmt.Errorf("failed to start MongoDB session: %v", err);
+	}
+	defer session.EndSession(ctx);
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx);
+	});
+	return err;
+}
+
+
 func (m *mongoDB) Close() {
 	mu.Lock()
 	defer mu.Unlock();
@@ -120,6 +136,59 @@ func (m *mongoDB) EstablishIndexes() {
 	voteCollection := m.GetCollection(utils.VOTES_COLLECTION);
 
 	voteCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key:"session_id", Value:1},
+			{Key:"question_id", Value:1},
+			{Key:"participant_id", Value:1},
+		},
+		// secret ballots carry no participant_id, they are deduplicated through participations.
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+			"participant_id": bson.M{"$exists": true},
+		}),
+	})
+
+	questionViewsCollection := m.GetCollection(utils.QUESTION_VIEWS_COLLECTION);
+
+	questionViewsCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key:"session_id", Value:1},
+			{Key:"question_id", Value:1},
+			{Key:"participant_id", Value:1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+
+	pulseSamplesCollection := m.GetCollection(utils.PULSE_SAMPLES_COLLECTION);
+
+	pulseSamplesCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key:"session_id", Value:1},
+			{Key:"question_id", Value:1},
+			{Key:"at", Value:1},
+		},
+	})
+
+	templatesCollection := m.GetCollection(utils.TEMPLATES_COLLECTION);
+
+	templatesCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key:"organizer_id", Value:1},
+			{Key:"created_at", Value:-1},
+		},
+	})
+
+	questionBankCollection := m.GetCollection(utils.QUESTION_BANK_COLLECTION);
+
+	questionBankCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key:"organizer_id", Value:1},
+			{Key:"tags", Value:1},
+		},
+	})
+
+	participationsCollection := m.GetCollection(utils.PARTICIPATIONS_COLLECTION);
+
+	participationsCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
 		Keys: bson.D{
 			{Key:"session_id", Value:1},
 			{Key:"question_id", Value:1},
@@ -127,4 +196,15 @@ func (m *mongoDB) EstablishIndexes() {
 		},
 		Options: options.Index().SetUnique(true),
 	})
+
+	rosterCollection := m.GetCollection(utils.ROSTER_COLLECTION);
+
+	// roster uploads match voters on their external id, it identifies one voter per session.
+	rosterCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key:"session_id", Value:1},
+			{Key:"external_id", Value:1},
+		},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"external_id": bson.M{"$gt": ""}}),
+	})
 }
\ No newline at end of file
@@ -0,0 +1,58 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// csvWriter puts both tables in one file: the summary, an empty line, then the votes,
+// each with its own header row.
+type csvWriter struct {
+	writer *csv.Writer
+	anonymous bool
+	summaryStarted bool
+	votesStarted bool
+}
+
+func newCSVWriter(w io.Writer, anonymous bool) *csvWriter {
+	return &csvWriter{writer: csv.NewWriter(w), anonymous: anonymous};
+}
+
+func (c *csvWriter) WriteSummary(row SummaryRow) error {
+	if !c.summaryStarted {
+		c.summaryStarted = true;
+		if err := c.writer.Write(summaryHeader()); err != nil {
+			return err;
+		}
+	}
+	return c.writer.Write(cellValues(row.cells()));
+}
+
+func (c *csvWriter) WriteVote(row VoteRow) error {
+	if !c.votesStarted {
+		if err := c.startVotes(); err != nil {
+			return err;
+		}
+	}
+	return c.writer.Write(cellValues(row.cells(c.anonymous)));
+}
+
+func (c *csvWriter) startVotes() error {
+	c.votesStarted = true;
+	if c.summaryStarted {
+		if err := c.writer.Write(nil); err != nil {
+			return err;
+		}
+	}
+	return c.writer.Write(voteHeader(c.anonymous));
+}
+
+func (c *csvWriter) Close() error {
+	if !c.votesStarted {
+		if err := c.startVotes(); err != nil {
+			return err;
+		}
+	}
+	c.writer.Flush();
+	return c.writer.Error();
+}
@@ -0,0 +1,258 @@
+// Package exporter writes the results of a session for analysis outside the app: a summary per
+// question and option, then one row per vote. Rows are written as they come, so the votes can be
+// streamed from the database without holding them in memory.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+)
+
+const (
+	FormatCSV = "csv";
+	FormatJSONL = "jsonl";
+	FormatXLSX = "xlsx";
+)
+
+// Writer receives all summary rows, then the vote rows, then Close.
+type Writer interface {
+	WriteSummary(row SummaryRow) error
+	WriteVote(row VoteRow) error
+	Close() error
+}
+
+// New returns the writer of a format. Anonymous exports leave out the participant column.
+func New(format string, w io.Writer, anonymous bool) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, anonymous), nil;
+	case FormatJSONL:
+		return newJSONLWriter(w), nil;
+	case FormatXLSX:
+		return newXLSXWriter(w, anonymous);
+	}
+	return nil, fmt.Errorf("format must be '%s', '%s' or '%s'", FormatCSV, FormatJSONL, FormatXLSX);
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8";
+	case FormatJSONL:
+		return "application/x-ndjson";
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet";
+	}
+	return "application/octet-stream";
+}
+
+// count of one option of a question, or of one option on one row of a matrix question.
+// Questions without options (pulse) get a single row with the question totals.
+type SummaryRow struct {
+	QuestionID string `json:"questionId"`
+	Question string `json:"question"`
+	Type string `json:"type"`
+	Row string `json:"row,omitempty"`// matrix questions
+	OptionIndex *int `json:"optionIndex,omitempty"`
+	Option string `json:"option,omitempty"`
+	Count int `json:"count"`
+	Percentage float64 `json:"percentage"`
+	Suppressed bool `json:"suppressed,omitempty"`// privacy sessions: count withheld
+	Points int `json:"points,omitempty"`// allocation questions
+	TotalVotes int `json:"totalVotes"`
+	Voters int `json:"voters"`
+}
+
+// one vote. ParticipantID is empty in anonymous sessions, Weight in secret ballot sessions.
+type VoteRow struct {
+	VoteID string `json:"voteId"`
+	QuestionID string `json:"questionId"`
+	Question string `json:"question"`
+	ParticipantID string `json:"participantId,omitempty"`
+	SelectedOptions []int `json:"selectedOptions"`
+	Selected []string `json:"selected"`
+	OtherText string `json:"otherText,omitempty"`
+	Availability []string `json:"availability,omitempty"`
+	Allocation []int `json:"allocation,omitempty"`
+	RowAnswers []int `json:"rowAnswers,omitempty"`
+	Weight float64 `json:"weight,omitempty"`
+	Retracted bool `json:"retracted,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Summarize turns the results of a question into summary rows.
+func Summarize(question models.Question, results models.QuestionResult) []SummaryRow {
+	base := SummaryRow{
+		QuestionID: question.ID.Hex(),
+		Question: question.Text,
+		Type: question.Type,
+		TotalVotes: results.TotalVotes,
+		Voters: results.VotersCount,
+	};
+
+	rows := []SummaryRow{};
+	add := func(rowText string, option models.OptionCount) {
+		row := base;
+		index := option.Index;
+		row.Row = rowText;
+		row.OptionIndex = &index;
+		row.Option = option.Text;
+		row.Count = option.Count;
+		row.Percentage = math.Round(option.Percentage*100) / 100;
+		row.Points = option.Points;
+		row.Suppressed = option.Suppressed;
+		rows = append(rows, row);
+	}
+
+	if len(results.Rows) > 0 {
+		for _, matrixRow := range results.Rows {
+			for _, option := range matrixRow.Options {
+				add(matrixRow.Text, option);
+			}
+		}
+		return rows;
+	}
+	for _, option := range results.Options {
+		add("", option);
+	}
+	if len(rows) == 0 {
+		rows = append(rows, base);
+	}
+	return rows;
+}
+
+// NewVoteRow describes a vote of the question, with the texts of the selected options.
+func NewVoteRow(session *models.Session, question models.Question, vote models.Vote) VoteRow {
+	row := VoteRow{
+		VoteID: vote.ID.Hex(),
+		QuestionID: vote.QuestionID.Hex(),
+		Question: question.Text,
+		SelectedOptions: vote.SelectedOptions,
+		Selected: []string{},
+		OtherText: vote.OtherText,
+		Availability: vote.Availability,
+		Allocation: vote.Allocation,
+		RowAnswers: vote.RowAnswers,
+		Weight: vote.Weight,
+		Retracted: vote.Retracted,
+		CreatedAt: vote.CreatedAt,
+		UpdatedAt: vote.UpdatedAt,
+	};
+	if row.SelectedOptions == nil {
+		row.SelectedOptions = []int{};
+	}
+	if !session.IsAnonymous() && !vote.ParticipantID.IsZero() {
+		row.ParticipantID = vote.ParticipantID.Hex();
+	}
+	// a weight can single out the voter of a secret ballot.
+	if session.SecretBallot {
+		row.Weight = 0;
+	}
+
+	for _, index := range vote.SelectedOptions {
+		if index >= 0 && index < len(question.Options) {
+			row.Selected = append(row.Selected, question.Options[index]);
+		}
+	}
+	return row;
+}
+
+// spreadsheet cell, kept numeric where the value is a number.
+type cell struct {
+	value string
+	numeric bool
+}
+
+func text(value string) cell {
+	return cell{value: value};
+}
+
+func integer(value int) cell {
+	return cell{value: strconv.Itoa(value), numeric: true};
+}
+
+func number(value float64) cell {
+	return cell{value: strconv.FormatFloat(value, 'f', -1, 64), numeric: true};
+}
+
+func summaryHeader() []string {
+	return []string{"question_id", "question", "type", "row", "option_index", "option", "count", "percentage", "points", "total_votes", "voters"};
+}
+
+func (r SummaryRow) cells() []cell {
+	optionIndex := text("");
+	if r.OptionIndex != nil {
+		optionIndex = integer(*r.OptionIndex);
+	}
+	points := text("");
+	if r.Type == utils.ALLOCATION {
+		points = integer(r.Points);
+	}
+	count, percentage := integer(r.Count), number(r.Percentage);
+	if r.Suppressed {
+		count, percentage = text("suppressed"), text("");
+	}
+	return []cell{
+		text(r.QuestionID), text(r.Question), text(r.Type), text(r.Row), optionIndex, text(r.Option),
+		count, percentage, points, integer(r.TotalVotes), integer(r.Voters),
+	};
+}
+
+func voteHeader(anonymous bool) []string {
+	header := []string{"vote_id", "question_id", "question"};
+	if !anonymous {
+		header = append(header, "participant_id");
+	}
+	return append(header, "selected_options", "selected", "other_text", "availability", "allocation", "row_answers", "weight", "retracted", "created_at", "updated_at");
+}
+
+// cells flattens list values with ";" so every vote stays one row.
+func (r VoteRow) cells(anonymous bool) []cell {
+	cells := []cell{text(r.VoteID), text(r.QuestionID), text(r.Question)};
+	if !anonymous {
+		cells = append(cells, text(r.ParticipantID));
+	}
+
+	updatedAt := "";
+	if r.UpdatedAt != nil {
+		updatedAt = r.UpdatedAt.UTC().Format(time.RFC3339);
+	}
+	weight := text("");
+	if r.Weight != 0 {
+		weight = number(r.Weight);
+	}
+	retracted := "";
+	if r.Retracted {
+		retracted = "yes";
+	}
+
+	return append(cells,
+		text(joinInts(r.SelectedOptions)), text(strings.Join(r.Selected, "; ")), text(r.OtherText),
+		text(strings.Join(r.Availability, ";")), text(joinInts(r.Allocation)), text(joinInts(r.RowAnswers)),
+		weight, text(retracted), text(r.CreatedAt.UTC().Format(time.RFC3339)), text(updatedAt),
+	);
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values));
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value);
+	}
+	return strings.Join(parts, ";");
+}
+
+func cellValues(cells []cell) []string {
+	values := make([]string, len(cells));
+	for i, c := range cells {
+		values[i] = c.value;
+	}
+	return values;
+}
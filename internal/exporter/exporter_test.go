@@ -0,0 +1,310 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSummarize(t *testing.T) {
+	question := models.Question{ID: primitive.NewObjectID(), Text: "Lunch?", Type: utils.SINGLE};
+
+	tests := []struct {
+		name string
+		question models.Question
+		results models.QuestionResult
+		want []SummaryRow
+	}{
+		{
+			name: "one row per option, percentages rounded",
+			question: question,
+			results: models.QuestionResult{TotalVotes: 3, VotersCount: 3, Options: []models.OptionCount{
+				{Index: 0, Text: "Pizza", Count: 2, Percentage: 66.66666},
+				{Index: 1, Text: "Sushi", Count: 1, Percentage: 33.33333},
+			}},
+			want: []SummaryRow{
+				{Option: "Pizza", Count: 2, Percentage: 66.67, TotalVotes: 3, Voters: 3},
+				{Option: "Sushi", Count: 1, Percentage: 33.33, TotalVotes: 3, Voters: 3},
+			},
+		},
+		{
+			name: "suppressed option",
+			question: question,
+			results: models.QuestionResult{TotalVotes: 9, VotersCount: 9, Options: []models.OptionCount{
+				{Index: 0, Text: "Pizza", Count: 9, Percentage: 100},
+				{Index: 1, Text: "Sushi", Suppressed: true},
+			}},
+			want: []SummaryRow{
+				{Option: "Pizza", Count: 9, Percentage: 100, TotalVotes: 9, Voters: 9},
+				{Option: "Sushi", Suppressed: true, TotalVotes: 9, Voters: 9},
+			},
+		},
+		{
+			name: "matrix rows",
+			question: models.Question{ID: question.ID, Text: "Rate", Type: utils.MATRIX},
+			results: models.QuestionResult{TotalVotes: 2, VotersCount: 1, Rows: []models.RowResult{
+				{Text: "Speed", Options: []models.OptionCount{{Index: 0, Text: "Low", Count: 1, Percentage: 100}}},
+				{Text: "Price", Options: []models.OptionCount{{Index: 1, Text: "High", Count: 1, Percentage: 100}}},
+			}},
+			want: []SummaryRow{
+				{Row: "Speed", Option: "Low", Count: 1, Percentage: 100, TotalVotes: 2, Voters: 1},
+				{Row: "Price", Option: "High", Count: 1, Percentage: 100, TotalVotes: 2, Voters: 1},
+			},
+		},
+		{
+			name: "no options gives the totals",
+			question: models.Question{ID: question.ID, Text: "Energy", Type: utils.PULSE},
+			results: models.QuestionResult{TotalVotes: 4, VotersCount: 4},
+			want: []SummaryRow{{TotalVotes: 4, Voters: 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := Summarize(tt.question, tt.results);
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.want));
+			}
+			for i, row := range rows {
+				want := tt.want[i];
+				want.QuestionID, want.Question, want.Type = tt.question.ID.Hex(), tt.question.Text, tt.question.Type;
+				want.OptionIndex = row.OptionIndex;
+				if !reflect.DeepEqual(row, want) {
+					t.Errorf("row %d = %+v, want %+v", i, row, want);
+				}
+			}
+		})
+	}
+}
+
+func TestNewVoteRow(t *testing.T) {
+	question := models.Question{ID: primitive.NewObjectID(), Text: "Lunch?", Options: []string{"Pizza", "Sushi", "Tacos"}};
+	participantID := primitive.NewObjectID();
+	vote := models.Vote{
+		ID: primitive.NewObjectID(),
+		QuestionID: question.ID,
+		ParticipantID: participantID,
+		SelectedOptions: []int{2, 0, 7},
+		Weight: 3,
+		CreatedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	};
+
+	tests := []struct {
+		name string
+		session models.Session
+		vote models.Vote
+		wantParticipant string
+		wantWeight float64
+		wantSelected []string
+	}{
+		{"identified", models.Session{}, vote, participantID.Hex(), 3, []string{"Tacos", "Pizza"}},
+		{"secret ballot drops participant and weight", models.Session{SecretBallot: true}, vote, "", 0, []string{"Tacos", "Pizza"}},
+		{"privacy drops participant", models.Session{Privacy: &models.PrivacySettings{MinCount: 5}}, vote, "", 3, []string{"Tacos", "Pizza"}},
+		{"no participant", models.Session{}, models.Vote{QuestionID: question.ID}, "", 0, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := NewVoteRow(&tt.session, question, tt.vote);
+			if row.ParticipantID != tt.wantParticipant {
+				t.Errorf("ParticipantID = %q, want %q", row.ParticipantID, tt.wantParticipant);
+			}
+			if row.Weight != tt.wantWeight {
+				t.Errorf("Weight = %v, want %v", row.Weight, tt.wantWeight);
+			}
+			if !reflect.DeepEqual(row.Selected, tt.wantSelected) {
+				t.Errorf("Selected = %v, want %v", row.Selected, tt.wantSelected);
+			}
+			if row.SelectedOptions == nil {
+				t.Error("SelectedOptions is nil, want an empty list");
+			}
+		})
+	}
+}
+
+func testRows() ([]SummaryRow, []VoteRow) {
+	index := 0;
+	summaries := []SummaryRow{
+		{QuestionID: "q1", Question: "Lunch?", Type: utils.SINGLE, OptionIndex: &index, Option: "Pizza", Count: 2, Percentage: 100, TotalVotes: 2, Voters: 2},
+		{QuestionID: "q1", Question: "Lunch?", Type: utils.SINGLE, OptionIndex: &index, Option: "Sushi", Suppressed: true},
+	};
+	votes := []VoteRow{{
+		VoteID: "v1", QuestionID: "q1", Question: "Lunch?", ParticipantID: "p1",
+		SelectedOptions: []int{0, 2}, Selected: []string{"Pizza", "Tacos"}, Retracted: true,
+		CreatedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}};
+	return summaries, votes;
+}
+
+func writeAll(t *testing.T, format string, anonymous bool, summaries []SummaryRow, votes []VoteRow) []byte {
+	t.Helper();
+	var buf bytes.Buffer;
+	writer, err := New(format, &buf, anonymous);
+	if err != nil {
+		t.Fatalf("New(%s) error = %v", format, err);
+	}
+	for _, row := range summaries {
+		if err := writer.WriteSummary(row); err != nil {
+			t.Fatalf("WriteSummary() error = %v", err);
+		}
+	}
+	for _, row := range votes {
+		if err := writer.WriteVote(row); err != nil {
+			t.Fatalf("WriteVote() error = %v", err);
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err);
+	}
+	return buf.Bytes();
+}
+
+func TestCSVWriter(t *testing.T) {
+	summaries, votes := testRows();
+	tests := []struct {
+		name string
+		anonymous bool
+		votes []VoteRow
+		wantVoteHeader []string
+		wantVote []string
+	}{
+		{"identified", false, votes, voteHeader(false), []string{"v1", "q1", "Lunch?", "p1", "0;2", "Pizza; Tacos", "", "", "", "", "", "yes", "2026-01-01T12:00:00Z", ""}},
+		{"anonymous", true, votes, voteHeader(true), []string{"v1", "q1", "Lunch?", "0;2", "Pizza; Tacos", "", "", "", "", "", "yes", "2026-01-01T12:00:00Z", ""}},
+		{"no votes still has the header", false, nil, voteHeader(false), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := csv.NewReader(bytes.NewReader(writeAll(t, FormatCSV, tt.anonymous, summaries, tt.votes)));
+			reader.FieldsPerRecord = -1;
+			records, err := reader.ReadAll();
+			if err != nil {
+				t.Fatalf("output is not csv: %v", err);
+			}
+
+			// summary header, two summaries, then (the empty line is skipped by the reader) the votes.
+			if !reflect.DeepEqual(records[0], summaryHeader()) {
+				t.Errorf("summary header = %v", records[0]);
+			}
+			if records[2][6] != "suppressed" || records[2][7] != "" {
+				t.Errorf("suppressed summary = %v, want the count withheld", records[2]);
+			}
+			if !reflect.DeepEqual(records[3], tt.wantVoteHeader) {
+				t.Errorf("vote header = %v, want %v", records[3], tt.wantVoteHeader);
+			}
+			if tt.wantVote == nil {
+				if len(records) != 4 {
+					t.Errorf("got %d records, want no vote rows", len(records));
+				}
+				return;
+			}
+			if !reflect.DeepEqual(records[4], tt.wantVote) {
+				t.Errorf("vote = %v, want %v", records[4], tt.wantVote);
+			}
+		})
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	summaries, votes := testRows();
+	lines := strings.Split(strings.TrimSpace(string(writeAll(t, FormatJSONL, false, summaries, votes))), "\n");
+
+	wantRecords := []string{"summary", "summary", "vote"};
+	if len(lines) != len(wantRecords) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(wantRecords));
+	}
+	for i, line := range lines {
+		var record map[string]interface{};
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i+1, err);
+		}
+		if record["record"] != wantRecords[i] {
+			t.Errorf("line %d record = %v, want %s", i+1, record["record"], wantRecords[i]);
+		}
+	}
+	if !strings.Contains(lines[1], `"suppressed":true`) {
+		t.Errorf("suppressed summary = %s", lines[1]);
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	summaries, votes := testRows();
+	data := writeAll(t, FormatXLSX, true, summaries, votes);
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)));
+	if err != nil {
+		t.Fatalf("output is not a zip: %v", err);
+	}
+	sheets := map[string]string{};
+	for _, file := range archive.File {
+		entry, err := file.Open();
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err);
+		}
+		content, _ := io.ReadAll(entry);
+		entry.Close();
+		sheets[file.Name] = string(content);
+	}
+
+	tests := []struct {
+		sheet string
+		contains []string
+		missing []string
+	}{
+		{"xl/worksheets/sheet1.xml", []string{`<row r="3">`, "Pizza", "suppressed"}, []string{`<row r="4">`}},
+		{"xl/worksheets/sheet2.xml", []string{`<row r="2">`, "Pizza; Tacos", "vote_id"}, []string{"participant_id", "p1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sheet, func(t *testing.T) {
+			sheet, found := sheets[tt.sheet];
+			if !found {
+				t.Fatalf("missing %s", tt.sheet);
+			}
+			for _, part := range tt.contains {
+				if !strings.Contains(sheet, part) {
+					t.Errorf("%s does not contain %q", tt.sheet, part);
+				}
+			}
+			for _, part := range tt.missing {
+				if strings.Contains(sheet, part) {
+					t.Errorf("%s contains %q", tt.sheet, part);
+				}
+			}
+		})
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		column int
+		want string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := columnName(tt.column); got != tt.want {
+			t.Errorf("columnName(%d) = %q, want %q", tt.column, got, tt.want);
+		}
+	}
+}
+
+func TestNewUnknownFormat(t *testing.T) {
+	if _, err := New("pdf", io.Discard, false); err == nil {
+		t.Error("New(pdf) error = nil, want a format error");
+	}
+}
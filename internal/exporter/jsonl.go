@@ -0,0 +1,35 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonlWriter writes one JSON object per line, "record" telling summaries and votes apart.
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	encoder := json.NewEncoder(w);
+	encoder.SetEscapeHTML(false);
+	return &jsonlWriter{encoder: encoder};
+}
+
+func (j *jsonlWriter) WriteSummary(row SummaryRow) error {
+	return j.encoder.Encode(struct {
+		Record string `json:"record"`
+		SummaryRow
+	}{Record: "summary", SummaryRow: row});
+}
+
+func (j *jsonlWriter) WriteVote(row VoteRow) error {
+	return j.encoder.Encode(struct {
+		Record string `json:"record"`
+		VoteRow
+	}{Record: "vote", VoteRow: row});
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil;
+}
@@ -0,0 +1,169 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// xlsxWriter builds a workbook with a "Summary" and a "Votes" sheet. The zip entries are written in
+// order and cells use inline strings (no shared string table), so rows go out as they arrive.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet *bufio.Writer
+	anonymous bool
+	rowNumber int
+	votesStarted bool
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/worksheets/sheet2.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`;
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`;
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>
+<sheet name="Summary" sheetId="1" r:id="rId1"/>
+<sheet name="Votes" sheetId="2" r:id="rId2"/>
+</sheets>
+</workbook>`;
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>
+</Relationships>`;
+
+const xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`;
+
+const xlsxSheetEnd = `</sheetData></worksheet>`;
+
+func newXLSXWriter(w io.Writer, anonymous bool) (*xlsxWriter, error) {
+	x := &xlsxWriter{archive: zip.NewWriter(w), anonymous: anonymous};
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	};
+	for _, part := range parts {
+		entry, err := x.archive.Create(part.name);
+		if err != nil {
+			return nil, err;
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err;
+		}
+	}
+
+	if err := x.startSheet("xl/worksheets/sheet1.xml", summaryHeader()); err != nil {
+		return nil, err;
+	}
+	return x, nil;
+}
+
+func (x *xlsxWriter) WriteSummary(row SummaryRow) error {
+	return x.writeRow(row.cells());
+}
+
+func (x *xlsxWriter) WriteVote(row VoteRow) error {
+	if !x.votesStarted {
+		if err := x.startVotes(); err != nil {
+			return err;
+		}
+	}
+	return x.writeRow(row.cells(x.anonymous));
+}
+
+func (x *xlsxWriter) startVotes() error {
+	x.votesStarted = true;
+	if err := x.endSheet(); err != nil {
+		return err;
+	}
+	return x.startSheet("xl/worksheets/sheet2.xml", voteHeader(x.anonymous));
+}
+
+func (x *xlsxWriter) Close() error {
+	if !x.votesStarted {
+		if err := x.startVotes(); err != nil {
+			return err;
+		}
+	}
+	if err := x.endSheet(); err != nil {
+		return err;
+	}
+	return x.archive.Close();
+}
+
+func (x *xlsxWriter) startSheet(name string, header []string) error {
+	entry, err := x.archive.Create(name);
+	if err != nil {
+		return err;
+	}
+	x.sheet = bufio.NewWriter(entry);
+	x.rowNumber = 0;
+	if _, err := x.sheet.WriteString(xlsxSheetStart); err != nil {
+		return err;
+	}
+
+	headerCells := make([]cell, len(header));
+	for i, title := range header {
+		headerCells[i] = text(title);
+	}
+	return x.writeRow(headerCells);
+}
+
+func (x *xlsxWriter) endSheet() error {
+	if _, err := x.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err;
+	}
+	return x.sheet.Flush();
+}
+
+func (x *xlsxWriter) writeRow(cells []cell) error {
+	x.rowNumber++;
+	row := strconv.Itoa(x.rowNumber);
+
+	x.sheet.WriteString(`<row r="` + row + `">`);
+	for column, c := range cells {
+		reference := columnName(column) + row;
+		switch {
+		case c.value == "":
+			continue;
+		case c.numeric:
+			x.sheet.WriteString(`<c r="` + reference + `"><v>` + c.value + `</v></c>`);
+		default:
+			x.sheet.WriteString(`<c r="` + reference + `" t="inlineStr"><is><t xml:space="preserve">`);
+			if err := xml.EscapeText(x.sheet, []byte(c.value)); err != nil {
+				return err;
+			}
+			x.sheet.WriteString(`</t></is></c>`);
+		}
+	}
+	_, err := x.sheet.WriteString(`</row>`);
+	return err;
+}
+
+// columnName returns the spreadsheet name of a 0-based column: A, B, ..., Z, AA, AB...
+func columnName(column int) string {
+	name := "";
+	for column >= 0 {
+		name = string(rune('A'+column%26)) + name;
+		column = column/26 - 1;
+	}
+	return name;
+}
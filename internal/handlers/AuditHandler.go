@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	KafkaC "RealTimePoll/internal/kafkaImpl"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+	"RealTimePoll/pkg/merkle"
+	"RealTimePoll/pkg/receipt"
+
+	"encoding/hex"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetReceiptHandler returns the signed receipt of a processed vote to the participant who cast it.
+func GetReceiptHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	participantID := r.URL.Query().Get("participantId")
+	if len(participantID) == 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "participantId is required")
+		return
+	}
+
+	voteReceipt, err := services.GetVoteReceipt(mux.Vars(r)["voteId"], participantID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Receipt not found, the vote may still be processing")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, voteReceipt)
+}
+
+// ReceiptPublicKeyHandler returns the ed25519 key that signs receipts and tally roots.
+func ReceiptPublicKeyHandler(w http.ResponseWriter, r *http.Request) {
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"algorithm": "ed25519",
+		"publicKey": receipt.PublicKey(),
+	})
+}
+
+// TallyAuditHandler returns the merkle roots published when the session closed.
+func TallyAuditHandler(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	audits, err := services.ListTallyAudits(sessionID)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load tally audit")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId": sessionID.Hex(),
+		"audits":    audits,
+	})
+}
+
+// InclusionProofHandler proves that ?ballotHash= is a leaf of the published tally of a question.
+func InclusionProofHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID, err := primitive.ObjectIDFromHex(vars["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(vars["questionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	ballotHash := r.URL.Query().Get("ballotHash")
+	if _, err := hex.DecodeString(ballotHash); err != nil || len(ballotHash) == 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "ballotHash must be a hex string")
+		return
+	}
+
+	audit, err := services.GetTallyAudit(sessionID, questionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	hashes, err := services.GetBallotHashes(sessionID, questionID)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load ballots")
+		return
+	}
+
+	leaves, err := KafkaC.MerkleLeaves(hashes)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load ballots")
+		return
+	}
+
+	if hex.EncodeToString(merkle.Root(leaves)) != audit.MerkleRoot {
+		utils.ErrorResponse(w, http.StatusConflict, "Stored ballots no longer match the published tally")
+		return
+	}
+
+	index := sort.SearchStrings(hashes, ballotHash)
+	if index == len(hashes) || hashes[index] != ballotHash {
+		utils.ErrorResponse(w, http.StatusNotFound, "Ballot not included in the tally")
+		return
+	}
+
+	proof, err := merkle.Proof(leaves, index)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId":  sessionID.Hex(),
+		"questionId": questionID.Hex(),
+		"ballotHash": ballotHash,
+		"leafIndex":  index,
+		"leafCount":  audit.LeafCount,
+		"merkleRoot": audit.MerkleRoot,
+		"signature":  audit.Signature,
+		"proof":      proof,
+	})
+}
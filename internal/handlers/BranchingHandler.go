@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NextQuestionHandler returns the participant's next question in a self-paced session, skipping
+// questions their earlier answers hide. The question served is counted as seen in the results.
+func NextQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil || session.Status != utils.ACTIVE {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Session not active or not found")
+		return
+	}
+
+	query := r.URL.Query()
+	participantID, err := resolveParticipant(session, query.Get("participantId"), query.Get("voterToken"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if participantID == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "participantId is required")
+		return
+	}
+	if _, err := primitive.ObjectIDFromHex(participantID); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid participant ID")
+		return
+	}
+
+	answers, err := services.GetParticipantAnswers(sessionID, participantID)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load answers")
+		return
+	}
+
+	// shuffled sessions serve questions in the participant's order, translated ones in their language.
+	locale := handlerUtil.RequestLocale(*session, r)
+	view := handlerUtil.ParticipantSessionView(session.Localized(locale), participantID)
+	question, found := view.NextQuestion(answers)
+	if !found {
+		utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+			"done":          true,
+			"answeredCount": len(answers),
+		})
+		return
+	}
+
+	if session.HasBranching() {
+		if err := services.RecordQuestionView(sessionID, question.ID, participantID); err != nil {
+			log.Println(err.Error())
+		}
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"done":          false,
+		"question":      question,
+		"locale":        view.Locale,
+		"answeredCount": len(answers),
+	})
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SeatsHandler returns the seats left on every capacity-limited option of a question and,
+// with ?participantId, where that participant stands on each waitlist.
+func SeatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	vars := mux.Vars(r)
+	sessionID, err := primitive.ObjectIDFromHex(vars["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+	questionID, err := primitive.ObjectIDFromHex(vars["questionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	question, found := session.FindQuestion(questionID)
+	if !found {
+		utils.ErrorResponse(w, http.StatusNotFound, "Question not found")
+		return
+	}
+	if !question.HasCapacity() {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Question has no capacity-limited options")
+		return
+	}
+
+	participantID := r.URL.Query().Get("participantId")
+	seats := []map[string]interface{}{}
+	for i, capacity := range question.OptionCapacities {
+		if capacity == 0 {
+			continue
+		}
+
+		taken, err := services.GetTakenSeats(sessionID, questionID, i)
+		if err != nil {
+			log.Println(err.Error())
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load seats")
+			return
+		}
+		remaining := capacity - taken
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		seat := map[string]interface{}{
+			"index":     i,
+			"text":      question.Options[i],
+			"capacity":  capacity,
+			"remaining": remaining,
+		}
+		if question.Waitlist {
+			waitlisted, err := services.GetWaitlistLength(sessionID, questionID, i)
+			if err != nil {
+				log.Println(err.Error())
+				utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load waitlist")
+				return
+			}
+			seat["waitlisted"] = waitlisted
+
+			if participantID != "" {
+				position, err := services.GetWaitlistPosition(sessionID, questionID, i, participantID)
+				if err != nil {
+					log.Println(err.Error())
+					utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load waitlist")
+					return
+				}
+				if position > 0 {
+					seat["waitlistPosition"] = position
+				}
+			}
+		}
+		seats = append(seats, seat)
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId":  sessionID.Hex(),
+		"questionId": questionID.Hex(),
+		"waitlist":   question.Waitlist,
+		"seats":      seats,
+	})
+}
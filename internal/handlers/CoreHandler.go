@@ -12,8 +12,10 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -30,17 +32,17 @@ func CreateNewPoll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newQuestionPoll.ID = primitive.NewObjectID()
-	newQuestionPoll.Status = utils.ACTIVE // by default is set to draft, unless an explicit request is made to active/close the poll.
-	newQuestionPoll.CreatedAt, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-	newQuestionPoll.UpdatedAt, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
-
 	if len(newQuestionPoll.JoinCode) > 0 {
 		log.Println("join code is given by user. This should be computer from server. Error")
 		utils.ErrorResponse(w, http.StatusBadRequest, "Do not provide join-code in body, it is supposed to be computed from the server.")
 		return
 	}
 
+	if err := prepareNewSession(&newQuestionPoll); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	newQuestionPoll.JoinCode = utils.GenerateJoinCode()
 
 	_, err := services.SavePollQuestions(newQuestionPoll)
@@ -51,12 +53,24 @@ func CreateNewPoll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Question poll is saved successfully. Id -" + newQuestionPoll.ID.Hex())
+	log.Printf("Question poll is saved successfully. Id - %s", newQuestionPoll.ID.Hex())
 	utils.JSONResponse(w, http.StatusCreated, map[string]string{
 		"message": "Question Poll saved successfully.",
 	})
 }
 
+// prepareNewSession gives a new session its id, status and timestamps, fills the defaults of its
+// questions and validates it. Templates and the question bank create sessions through it as well.
+func prepareNewSession(newQuestionPoll *models.Session) error {
+	newQuestionPoll.ID = primitive.NewObjectID()
+	newQuestionPoll.Status = utils.ACTIVE // by default is set to draft, unless an explicit request is made to active/close the poll.
+	newQuestionPoll.CreatedAt, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	newQuestionPoll.UpdatedAt, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+
+	handlerUtil.ApplySessionDefaults(newQuestionPoll)
+	return handlerUtil.ValidateSessionRequest(*newQuestionPoll)
+}
+
 func SubmitVoteHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
@@ -82,7 +96,7 @@ func SubmitVoteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = primitive.ObjectIDFromHex(payload.QuestionID)
+	questionID, err := primitive.ObjectIDFromHex(payload.QuestionID)
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
 		return
@@ -94,6 +108,63 @@ func SubmitVoteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	participantID, err := resolveParticipant(session, payload.ParticipantID, payload.VoterToken)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+	payload.ParticipantID = participantID
+
+	question, found := session.FindQuestion(questionID)
+	if !found {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Question not found in session")
+		return
+	}
+
+	if question.ClosedAt != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Question is closed")
+		return
+	}
+
+	if session.IsQuiz() && question.OpenedAt == nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Question is not open yet")
+		return
+	}
+
+	// the participant picked positions on their own option order.
+	payload.SelectedOptions = handlerUtil.CanonicalSelection(*session, question, payload.ParticipantID, payload.SelectedOptions)
+
+	if err := handlerUtil.ValidateAvailability(question, payload.Availability); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := handlerUtil.ValidateAllocation(question, payload.Allocation); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := handlerUtil.ValidateRowAnswers(question, payload.RowAnswers); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch question.Type {
+	case utils.SCHEDULE:
+		payload.SelectedOptions = handlerUtil.AvailableSlots(payload.Availability)
+	case utils.ALLOCATION:
+		payload.SelectedOptions = handlerUtil.AllocatedOptions(payload.Allocation)
+	case utils.MATRIX:
+		payload.SelectedOptions = append([]int{}, payload.RowAnswers...)
+	}
+
+	if err := handlerUtil.ValidateSelectedOptions(question, payload.SelectedOptions); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := handlerUtil.ValidateOtherText(question, payload.SelectedOptions, payload.OtherText); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	//creating vote event for kafka
 	voteEvent := KafkaC.VoteSubmittedEvent{
 		EventID:         primitive.NewObjectID().Hex(), // Unique event ID
@@ -103,13 +174,25 @@ func SubmitVoteHandler(w http.ResponseWriter, r *http.Request) {
 		QuestionID:      payload.QuestionID,
 		ParticipantID:   payload.ParticipantID,
 		SelectedOptions: payload.SelectedOptions,
+		OtherText:       strings.TrimSpace(payload.OtherText),
+		Availability:    payload.Availability,
+		Allocation:      payload.Allocation,
+		RowAnswers:      payload.RowAnswers,
 		Timestamp:       time.Now(),
-		IPAddress:       handlerUtil.GetIPAddress(r),
-		UserAgent:       r.UserAgent(),
 	}
 
-	log.Println("Here is the vote event : ")
-	log.Println(voteEvent)
+	// secret ballots never carry network details that could identify the voter.
+	if !session.SecretBallot {
+		voteEvent.IPAddress = handlerUtil.GetIPAddress(r)
+		voteEvent.UserAgent = r.UserAgent()
+
+		log.Println("Here is the vote event : ")
+		log.Println(voteEvent)
+	} else if err := KafkaC.SealVoteEvent(&voteEvent); err != nil {
+		log.Printf("Failed to seal secret ballot: %v", err)
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to process vote")
+		return
+	}
 
 	// send to kafka
 	if err := KafkaC.ProduceVoteSubmitted(voteEvent); err != nil {
@@ -129,6 +212,87 @@ func SubmitVoteHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// RetractVoteHandler withdraws a participant's vote on a question whose policy allows it.
+func RetractVoteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try DELETE !")
+		return
+	}
+
+	var payload models.RetractVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := handlerUtil.ValidateRetractVoteRequest(payload); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(payload.SessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(payload.QuestionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil || session.Status != utils.ACTIVE {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Session not active or not found")
+		return
+	}
+
+	participantID, err := resolveParticipant(session, payload.ParticipantID, payload.VoterToken)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+	payload.ParticipantID = participantID
+
+	question, found := session.FindQuestion(questionID)
+	if !found {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Question not found in session")
+		return
+	}
+
+	if question.ClosedAt != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Question is closed")
+		return
+	}
+
+	if !question.AllowsRetraction() {
+		utils.ErrorResponse(w, http.StatusForbidden, "Votes on this question cannot be retracted")
+		return
+	}
+
+	retractEvent := KafkaC.VoteSubmittedEvent{
+		EventID:       primitive.NewObjectID().Hex(),
+		Type:          utils.VOTE_RETRACTED_EVENT,
+		SessionID:     payload.SessionID,
+		QuestionID:    payload.QuestionID,
+		ParticipantID: payload.ParticipantID,
+		Timestamp:     time.Now(),
+	}
+
+	if err := KafkaC.ProduceVoteSubmitted(retractEvent); err != nil {
+		log.Printf("Failed to send vote retraction to Kafka: %v", err)
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to process retraction")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusAccepted, map[string]interface{}{
+		"message":    "Vote retraction accepted for processing",
+		"sessionId":  payload.SessionID,
+		"questionId": payload.QuestionID,
+	})
+}
+
 func UpdateSessionHandler(w http.ResponseWriter, r *http.Request) {
 
 	if r.Method != http.MethodPatch {
@@ -163,7 +327,15 @@ func UpdateSessionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = services.UpdateSessionStatus(SessionHexID, status)
+	if status == utils.CLOSED {
+		err = KafkaC.CloseSession(SessionHexID, "organizer")
+	} else {
+		err = services.UpdateSessionStatus(SessionHexID, status)
+	}
+	if err == KafkaC.ErrClosingInProgress {
+		utils.ErrorResponse(w, http.StatusConflict, "The session is being closed, try again in a moment.")
+		return
+	}
 	if err != nil {
 		log.Println(err.Error())
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Something went wrong while updating status.")
@@ -175,3 +347,96 @@ func UpdateSessionHandler(w http.ResponseWriter, r *http.Request) {
 	})
 
 }
+
+// GetSessionHandler returns a session as participants are allowed to see it.
+func GetSessionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	// with ?participantId= the questions and options come in that participant's order,
+	// with ?locale= or Accept-Language in the closest translation.
+	localized := session.Localized(handlerUtil.RequestLocale(*session, r))
+	utils.JSONResponse(w, http.StatusOK, handlerUtil.ParticipantSessionView(localized, r.URL.Query().Get("participantId")))
+}
+
+// OpenQuestionHandler starts the answer window of a question (quiz speed bonus counts from here).
+// In a quiz the question still open is closed first, which publishes the leaderboard.
+func OpenQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	updateQuestionState(w, r, "open")
+}
+
+// CloseQuestionHandler stops a question from accepting answers and broadcasts its final
+// results. For quizzes the leaderboard is published to all clients afterwards.
+func CloseQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	updateQuestionState(w, r, "close")
+}
+
+func updateQuestionState(w http.ResponseWriter, r *http.Request, action string) {
+	if r.Method != http.MethodPatch {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try PATCH !")
+		return
+	}
+
+	vars := mux.Vars(r)
+	sessionID, err := primitive.ObjectIDFromHex(vars["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(vars["questionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	question, found := session.FindQuestion(questionID)
+	if !found {
+		utils.ErrorResponse(w, http.StatusNotFound, "Question not found in session")
+		return
+	}
+
+	if action == "open" {
+		err = KafkaC.CloseOpenQuizQuestions(session, questionID)
+		if err == nil {
+			err = services.SetQuestionOpened(sessionID, questionID)
+		}
+	} else {
+		err = KafkaC.CloseQuestion(session, question, "organizer")
+	}
+
+	if err == KafkaC.ErrClosingInProgress {
+		utils.ErrorResponse(w, http.StatusConflict, "The question is being closed, try again in a moment.")
+		return
+	}
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Something went wrong while updating question.")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]string{
+		"message": fmt.Sprintf("Question %s of session %s is now %sd", questionID.Hex(), sessionID.Hex(), action),
+	})
+}
+
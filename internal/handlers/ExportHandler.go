@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"RealTimePoll/internal/exporter"
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	KafkaC "RealTimePoll/internal/kafkaImpl"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportSessionHandler downloads the results of a session as csv (default), jsonl or xlsx: one
+// summary row per question and option, then every vote. Votes are streamed from a cursor, and
+// participant ids are left out of secret ballot sessions. Privacy sessions export only the
+// summaries participants were shown, never the votes.
+func ExportSessionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = exporter.FormatCSV
+	}
+	switch format {
+	case exporter.FormatCSV, exporter.FormatJSONL, exporter.FormatXLSX:
+	default:
+		utils.ErrorResponse(w, http.StatusBadRequest, "format must be 'csv', 'jsonl' or 'xlsx'")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	// summaries are small and computed before anything is sent, so failures still get a proper error.
+	summaries := []exporter.SummaryRow{}
+	questions := make(map[primitive.ObjectID]models.Question, len(session.Questions))
+	for _, question := range session.Questions {
+		questions[question.ID] = question
+		var results *models.QuestionResult
+		if session.HasPrivacy() {
+			results, err = KafkaC.PublishedResults(session, question.ID)
+		} else {
+			results, err = KafkaC.QuestionResults(session, question.ID)
+		}
+		if err != nil {
+			log.Println(err.Error())
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load results")
+			return
+		}
+		summaries = append(summaries, exporter.Summarize(question, *results)...)
+	}
+
+	anonymous := session.IsAnonymous()
+	w.Header().Set("Content-Type", exporter.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"session-%s.%s\"", sessionID.Hex(), format))
+	w.WriteHeader(http.StatusOK)
+
+	writer, err := exporter.New(format, w, anonymous)
+	if err != nil {
+		log.Printf("Export of session %s failed: %v", sessionID.Hex(), err)
+		return
+	}
+
+	for _, summary := range summaries {
+		if err := writer.WriteSummary(summary); err != nil {
+			log.Printf("Export of session %s failed: %v", sessionID.Hex(), err)
+			return
+		}
+	}
+
+	if !session.HasPrivacy() {
+		err = services.StreamSessionVotes(r.Context(), sessionID, func(vote models.Vote) error {
+			return writer.WriteVote(exporter.NewVoteRow(session, questions[vote.QuestionID], vote))
+		})
+		if err != nil {
+			// the status is already sent, the client sees a truncated file.
+			log.Printf("Export of session %s failed: %v", sessionID.Hex(), err)
+			return
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("Export of session %s failed: %v", sessionID.Hex(), err)
+	}
+}
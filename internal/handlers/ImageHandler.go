@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"RealTimePoll/internal/storage"
+	"RealTimePoll/internal/utils"
+
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// UploadImageHandler stores an image for use in option images. The image is sent as the "image" field
+// of a multipart form or as the raw request body. Its type is sniffed from the content, and a thumbnail
+// is stored next to it.
+func UploadImageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	// room for the multipart envelope around the image.
+	r.Body = http.MaxBytesReader(w, r.Body, storage.MaxImageBytes+64<<10)
+
+	var reader io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("image")
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Multipart uploads need an 'image' file field within the size limit")
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, storage.MaxImageBytes+1))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusRequestEntityTooLarge, "Image is too large")
+		return
+	}
+	if len(data) == 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Image is empty")
+		return
+	}
+	if len(data) > storage.MaxImageBytes {
+		utils.ErrorResponse(w, http.StatusRequestEntityTooLarge, "Image is too large")
+		return
+	}
+
+	image, err := storage.SaveImage(r.Context(), storage.Default(), data)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusCreated, image)
+}
+
+// BlobHandler serves stored images and thumbnails, used by the local blob store.
+func BlobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	key := mux.Vars(r)["key"]
+	if !strings.HasPrefix(key, "images/") && !strings.HasPrefix(key, "thumbnails/") {
+		utils.ErrorResponse(w, http.StatusNotFound, "Blob not found")
+		return
+	}
+
+	content, contentType, err := storage.Default().Get(r.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		utils.ErrorResponse(w, http.StatusNotFound, "Blob not found")
+		return
+	}
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load blob")
+		return
+	}
+	defer content.Close()
+
+	// blobs never change once stored, their keys are unique.
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
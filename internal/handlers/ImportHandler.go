@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	"RealTimePoll/internal/importer"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ImportSessionHandler creates a draft session from a JSON, YAML, CSV or Markdown definition, sent
+// as the raw body or as the 'file' field of a multipart form. ?format= overrides the detection
+// from the file name or Content-Type, ?title= names sessions whose document has no title.
+// Invalid documents are answered with every problem and the line it was found on.
+func ImportSessionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	// room for the multipart envelope around the document.
+	r.Body = http.MaxBytesReader(w, r.Body, importer.MaxDocumentBytes+64<<10)
+
+	var reader io.Reader = r.Body
+	fileName, contentType := "", r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Multipart uploads need a 'file' field within the size limit")
+			return
+		}
+		defer file.Close()
+		reader = file
+		fileName, contentType = header.Filename, header.Header.Get("Content-Type")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, importer.MaxDocumentBytes+1))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusRequestEntityTooLarge, "Document is too large")
+		return
+	}
+
+	query := r.URL.Query()
+	format := strings.ToLower(query.Get("format"))
+	if format == "" {
+		format = importer.DetectFormat(fileName, contentType)
+	}
+	if format == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Unknown format, pass ?format=json, yaml, csv or markdown")
+		return
+	}
+
+	session, err := importer.Parse(format, data, query.Get("title"))
+	if err != nil {
+		var importError *importer.Error
+		if errors.As(err, &importError) {
+			utils.JSONResponse(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":  "The session definition is invalid",
+				"errors": importError.Errors,
+			})
+			return
+		}
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session.OrganizerId = organizerID
+	sessionID, err := services.SavePollQuestions(*session)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to save imported session")
+		return
+	}
+
+	log.Printf("Imported %s session %s with %d questions", format, *sessionID, len(session.Questions))
+	utils.JSONResponse(w, http.StatusCreated, map[string]interface{}{
+		"message":       "Session imported as draft.",
+		"sessionId":     *sessionID,
+		"joinCode":      session.JoinCode,
+		"status":        session.Status,
+		"questionCount": len(session.Questions),
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OtherTextsHandler returns the "Other (please specify)" answers of a question grouped by
+// normalized text with their counts.
+func OtherTextsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	vars := mux.Vars(r)
+	sessionID, err := primitive.ObjectIDFromHex(vars["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(vars["questionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	question, found := session.FindQuestion(questionID)
+	if !found {
+		utils.ErrorResponse(w, http.StatusNotFound, "Question not found in session")
+		return
+	}
+
+	groups, err := services.GroupOtherTexts(sessionID, questionID)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load other texts")
+		return
+	}
+
+	total := 0
+	for _, group := range groups {
+		total += group.Count
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId":       sessionID.Hex(),
+		"questionId":      questionID.Hex(),
+		"freeTextOptions": question.FreeTextOptions,
+		"total":           total,
+		"groups":          groups,
+	})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/utils"
+
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JoinSessionHandler hands out a fresh participant id with its participant token. The token
+// binds websocket connections to the id, so receipts, ranks and after-vote results reach only
+// that participant. Invite-only sessions join by redeeming an invite token instead.
+func JoinSessionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if session.InviteOnly {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Session is invite-only, redeem the invite token instead")
+		return
+	}
+
+	participantID := primitive.NewObjectID().Hex()
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId":        sessionID.Hex(),
+		"participantId":    participantID,
+		"participantToken": utils.ParticipantToken(sessionID.Hex(), participantID),
+	})
+}
+
+// VerifyParticipantConnection returns the participant id a websocket connection is bound to:
+// the roster voter of the voter token in invite-only sessions, elsewhere the participant id
+// its participant token was issued for.
+func VerifyParticipantConnection(sessionIDHex, participantID, voterToken, participantToken string) (string, error) {
+	sessionID, err := primitive.ObjectIDFromHex(sessionIDHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid session ID")
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		return "", fmt.Errorf("session not found")
+	}
+
+	if session.InviteOnly {
+		return resolveParticipant(session, participantID, voterToken)
+	}
+
+	if !utils.ValidParticipantToken(sessionIDHex, participantID, participantToken) {
+		return "", fmt.Errorf("participantToken does not belong to this participant")
+	}
+	return participantID, nil
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PulseTimelineHandler returns the sampled sentiment of a pulse question, each sample with its offset
+// from the moment the question was opened so it can be laid over the talk timeline. Optional
+// ?from and ?to (RFC3339) narrow the window.
+func PulseTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	vars := mux.Vars(r)
+	sessionID, err := primitive.ObjectIDFromHex(vars["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+	questionID, err := primitive.ObjectIDFromHex(vars["questionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+	question, found := session.FindQuestion(questionID)
+	if !found {
+		utils.ErrorResponse(w, http.StatusNotFound, "Question not found in session")
+		return
+	}
+	if question.Type != utils.PULSE {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Question is not a pulse question")
+		return
+	}
+
+	var from, to time.Time
+	query := r.URL.Query()
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "from must be an RFC3339 time")
+			return
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "to must be an RFC3339 time")
+			return
+		}
+	}
+
+	samples, err := services.ListPulseSamples(sessionID, questionID, from, to)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load pulse samples")
+		return
+	}
+
+	// without an open time the first sample starts the timeline.
+	var start time.Time
+	if question.OpenedAt != nil {
+		start = *question.OpenedAt
+	} else if len(samples) > 0 {
+		start = samples[0].At
+	}
+
+	timeline := make([]map[string]interface{}, len(samples))
+	for i, sample := range samples {
+		timeline[i] = map[string]interface{}{
+			"at":            sample.At,
+			"offsetSeconds": sample.At.Sub(start).Seconds(),
+			"count":         sample.Count,
+			"mean":          sample.Mean,
+			"distribution":  sample.Distribution,
+		}
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId":  sessionID.Hex(),
+		"questionId": questionID.Hex(),
+		"sliderMin":  question.SliderMin,
+		"sliderMax":  question.SliderMax,
+		"openedAt":   question.OpenedAt,
+		"closedAt":   question.ClosedAt,
+		"samples":    timeline,
+	})
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	KafkaC "RealTimePoll/internal/kafkaImpl"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AskQuestionHandler lets a participant submit a question to the speaker.
+func AskQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	var payload models.AudienceQuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := handlerUtil.ValidateAudienceQuestionRequest(payload); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil || session.Status != utils.ACTIVE {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Session not active or not found")
+		return
+	}
+
+	status := utils.QNA_APPROVED
+	if session.QnAModeration {
+		status = utils.QNA_PENDING
+	}
+
+	nowTime, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	question := models.AudienceQuestion{
+		ID:            primitive.NewObjectID(),
+		SessionID:     sessionID,
+		ParticipantID: payload.ParticipantID,
+		AuthorName:    strings.TrimSpace(payload.AuthorName),
+		Text:          strings.TrimSpace(payload.Text),
+		Status:        status,
+		CreatedAt:     nowTime,
+		UpdatedAt:     nowTime,
+	}
+
+	if err := services.SaveAudienceQuestion(question); err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to save question")
+		return
+	}
+
+	if err := KafkaC.PublishQnAUpdate(sessionID); err != nil {
+		log.Printf("Failed to publish Q&A update: %v", err)
+	}
+
+	utils.JSONResponse(w, http.StatusCreated, map[string]interface{}{
+		"message":  "Question submitted",
+		"question": question,
+	})
+}
+
+// UpvoteQuestionHandler counts one upvote per participant on a visible audience question.
+func UpvoteQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["qnaId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	var payload models.UpvoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ParticipantID == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "participantId is required")
+		return
+	}
+
+	question, err := services.GetAudienceQuestion(questionID)
+	if err != nil || question.Status != utils.QNA_APPROVED {
+		utils.ErrorResponse(w, http.StatusNotFound, "Question not found or not open for upvotes")
+		return
+	}
+
+	if err := services.UpvoteAudienceQuestion(questionID, payload.ParticipantID); err != nil {
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	if err := KafkaC.PublishQnAUpdate(question.SessionID); err != nil {
+		log.Printf("Failed to publish Q&A update: %v", err)
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]string{
+		"message": "Upvote counted",
+	})
+}
+
+// ListQuestionsHandler returns the ranked approved Q&A of a session.
+func ListQuestionsHandler(w http.ResponseWriter, r *http.Request) {
+	listAudienceQuestions(w, r, false)
+}
+
+// ModerationQueueHandler returns every audience question of a session, including pending and hidden ones.
+func ModerationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	listAudienceQuestions(w, r, true)
+}
+
+func listAudienceQuestions(w http.ResponseWriter, r *http.Request, withModerationQueue bool) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	// the queue holds unmoderated questions and who asked them.
+	if withModerationQueue {
+		session, err := repository.GetSessionByID(sessionID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+			utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+			return
+		}
+	}
+
+	questions, err := services.ListAudienceQuestions(sessionID, withModerationQueue)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load questions")
+		return
+	}
+
+	if !withModerationQueue {
+		for i := range questions {
+			questions[i].ParticipantID = ""
+		}
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId": sessionID.Hex(),
+		"questions": questions,
+	})
+}
+
+// ModerateQuestionHandler applies ?action=approve|hide|answer|pin|unpin to an audience question.
+func ModerateQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try PATCH !")
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["qnaId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	action := r.URL.Query().Get("action")
+	switch action {
+	case "approve", "hide", "answer", "pin", "unpin":
+		// valid action
+	default:
+		utils.ErrorResponse(w, http.StatusBadRequest, "The provided action is invalid. Only 'approve', 'hide', 'answer', 'pin' and 'unpin' are allowed.")
+		return
+	}
+
+	audienceQuestion, err := services.GetAudienceQuestion(questionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Question not found")
+		return
+	}
+	session, err := repository.GetSessionByID(audienceQuestion.SessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	question, err := services.ModerateAudienceQuestion(questionID, action)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err := KafkaC.PublishQnAUpdate(question.SessionID); err != nil {
+		log.Printf("Failed to publish Q&A update: %v", err)
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]string{
+		"message": "Question updated: " + action,
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LeaderboardHandler returns the standings of a quiz session. ?limit=N returns the top N only.
+func LeaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	limit := 0
+	if limitParam := r.URL.Query().Get("limit"); len(limitParam) > 0 {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 0 {
+			utils.ErrorResponse(w, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if !session.IsQuiz() {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Session is not a quiz")
+		return
+	}
+
+	leaderboard, err := services.GetLeaderboard(sessionID, limit)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load leaderboard")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"leaderboard": leaderboard,
+		"final":       session.Status == utils.CLOSED,
+	})
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UploadRosterHandler replaces the eligible voters of a session from a JSON array or a CSV
+// file (Content-Type: text/csv), until the first vote is cast. Every voter gets a fresh invite
+// token, returned only in this response. The entry ids are the participant ids the voters cast
+// votes as; a voter already on the roster (same externalId, or email) keeps their entry id.
+func UploadRosterHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try PUT !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	var entries []models.RosterEntry
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		entries, err = handlerUtil.ParseRosterCSV(r.Body)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Something wrong with request body format")
+		return
+	}
+
+	if err := handlerUtil.ValidateRosterEntries(entries); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	nowTime, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	invites := make([]models.RosterInvite, len(entries))
+	for i := range entries {
+		token := utils.GenerateInviteToken()
+		entries[i].SessionID = sessionID
+		entries[i].InviteTokenHash = utils.HashToken(token)
+		entries[i].VotedAt = nil
+		entries[i].CreatedAt = nowTime
+
+		invites[i] = models.RosterInvite{
+			Email:       entries[i].Email,
+			ExternalID:  entries[i].ExternalID,
+			InviteToken: token,
+		}
+	}
+
+	err = services.ReplaceRoster(sessionID, entries)
+	if err == services.ErrRosterLocked {
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to save roster")
+		return
+	}
+
+	// entry ids are known once saved: voters already on the roster keep theirs.
+	for i := range entries {
+		invites[i].EntryID = entries[i].ID.Hex()
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"message": "Roster saved successfully. Invite tokens are not shown again.",
+		"entries": entries,
+		"invites": invites,
+	})
+}
+
+func GetRosterHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	entries, err := services.GetRoster(sessionID)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load roster")
+		return
+	}
+
+	totalWeight := 0.0
+	for _, entry := range entries {
+		totalWeight += entry.Weight
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId":   sessionID.Hex(),
+		"entries":     entries,
+		"totalWeight": totalWeight,
+	})
+}
+
+// ReissueInviteHandler issues a new invite token for one voter; the old invite token and the
+// voter token it was redeemed for stop working.
+func ReissueInviteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	vars := mux.Vars(r)
+	sessionID, err := primitive.ObjectIDFromHex(vars["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	entryID, err := primitive.ObjectIDFromHex(vars["entryId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid roster entry ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	token := utils.GenerateInviteToken()
+	if err := services.SetInviteTokenHash(sessionID, entryID, utils.HashToken(token)); err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, models.RosterInvite{
+		EntryID:     entryID.Hex(),
+		InviteToken: token,
+	})
+}
+
+// TurnoutHandler reports which roster voters have voted and which have not.
+func TurnoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err := handlerUtil.CheckSessionOwner(r, *session); err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	entries, err := services.GetRoster(sessionID)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load roster")
+		return
+	}
+
+	turnout := make([]models.RosterTurnout, len(entries))
+	voted, totalWeight, votedWeight := 0, 0.0, 0.0
+	for i, entry := range entries {
+		turnout[i] = models.RosterTurnout{RosterEntry: entry, Voted: entry.VotedAt != nil}
+		totalWeight += entry.Weight
+		if entry.VotedAt != nil {
+			voted++
+			votedWeight += entry.Weight
+		}
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId":   sessionID.Hex(),
+		"total":       len(entries),
+		"voted":       voted,
+		"notVoted":    len(entries) - voted,
+		"totalWeight": totalWeight,
+		"votedWeight": votedWeight,
+		"entries":     turnout,
+	})
+}
+
+// RedeemInviteHandler trades a voter's one-time invite token for the voter token their votes
+// in an invite-only session are cast with. The voter token is returned only in this response.
+func RedeemInviteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	var payload models.RedeemInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.InviteToken == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "inviteToken is required")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil || !session.InviteOnly {
+		utils.ErrorResponse(w, http.StatusNotFound, "Invite-only session not found")
+		return
+	}
+
+	voterToken := utils.GenerateInviteToken()
+	entry, err := services.RedeemInviteToken(sessionID, payload.InviteToken, utils.HashToken(voterToken))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"sessionId":     sessionID.Hex(),
+		"participantId": entry.ID.Hex(),
+		"voterToken":    voterToken,
+	})
+}
+
+// resolveParticipant returns the participant id a request votes as. In invite-only sessions
+// the voter token must belong to a roster voter, whose entry id becomes the participant id;
+// elsewhere the participant id is required and voter tokens are ignored.
+func resolveParticipant(session *models.Session, participantID, voterToken string) (string, error) {
+	if !session.InviteOnly {
+		if participantID == "" {
+			return "", fmt.Errorf("participantId is required")
+		}
+		return participantID, nil
+	}
+
+	if voterToken == "" {
+		return "", fmt.Errorf("voterToken is required for this session, redeem the invite token first")
+	}
+
+	entry, err := services.GetRosterEntryByVoterToken(session.ID, voterToken)
+	if err != nil {
+		return "", err
+	}
+
+	if participantID != "" && participantID != entry.ID.Hex() {
+		return "", fmt.Errorf("voterToken does not belong to this participant")
+	}
+	return entry.ID.Hex(), nil
+}
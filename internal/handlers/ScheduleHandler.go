@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	KafkaC "RealTimePoll/internal/kafkaImpl"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/utils"
+
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ScheduleICSHandler exports the best ranked slot of a schedule question as an iCalendar file.
+func ScheduleICSHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	vars := mux.Vars(r)
+	sessionID, err := primitive.ObjectIDFromHex(vars["sessionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+	questionID, err := primitive.ObjectIDFromHex(vars["questionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	question, found := session.FindQuestion(questionID)
+	if !found {
+		utils.ErrorResponse(w, http.StatusNotFound, "Question not found")
+		return
+	}
+	if question.Type != utils.SCHEDULE {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Question is not a schedule question")
+		return
+	}
+
+	results, err := KafkaC.QuestionResults(session, questionID)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load results")
+		return
+	}
+	if len(results.Ranking) == 0 || results.Options[results.Ranking[0]].Count == 0 {
+		utils.ErrorResponse(w, http.StatusNotFound, "No slot has availability yet")
+		return
+	}
+
+	winner := results.Ranking[0]
+	slot := question.Slots[winner]
+	option := results.Options[winner]
+	event := utils.CalendarEvent{
+		UID:         fmt.Sprintf("%s-%s-%d@realtimepoll", sessionID.Hex(), questionID.Hex(), winner),
+		Summary:     fmt.Sprintf("%s: %s", session.Title, question.Text),
+		Description: slotDescription(option),
+		Start:       slot.Start,
+		End:         slot.End,
+		Timezone:    slot.Timezone,
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.ics\"", questionID.Hex()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(utils.BuildICS([]utils.CalendarEvent{event}, time.Now()))
+}
+
+func slotDescription(option models.OptionCount) string {
+	description := fmt.Sprintf("%s\nAvailable: %d (%d if need be)", option.Text, option.Count, option.IfNeedBe)
+	for _, participant := range option.Available {
+		name := participant.Name
+		if name == "" {
+			name = participant.ParticipantID
+		}
+		if participant.Answer == utils.AVAILABILITY_IF_NEED_BE {
+			name += " (if need be)"
+		}
+		description += "\n- " + name
+	}
+	return description
+}
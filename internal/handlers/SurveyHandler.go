@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	KafkaC "RealTimePoll/internal/kafkaImpl"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/utils"
+
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SubmitSurveyHandler accepts answers for many questions of a session in one request.
+// The answers are validated together and published as a single event, so the consumer
+// stores all of them or none.
+func SubmitSurveyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	var payload models.SurveyRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := handlerUtil.ValidateSurveyRequest(payload); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(payload.SessionID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	session, err := repository.GetSessionByID(sessionID)
+	if err != nil || session.Status != utils.ACTIVE {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Session not active or not found")
+		return
+	}
+
+	participantID, err := resolveParticipant(session, payload.ParticipantID, payload.VoterToken)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+	payload.ParticipantID = participantID
+
+	// the participant picked positions on their own option order.
+	for i, answer := range payload.Answers {
+		questionID, err := primitive.ObjectIDFromHex(answer.QuestionID)
+		if err != nil {
+			continue
+		}
+		if question, found := session.FindQuestion(questionID); found {
+			payload.Answers[i].SelectedOptions = handlerUtil.CanonicalSelection(*session, question, payload.ParticipantID, answer.SelectedOptions)
+			switch question.Type {
+			case utils.SCHEDULE:
+				payload.Answers[i].SelectedOptions = handlerUtil.AvailableSlots(answer.Availability)
+			case utils.ALLOCATION:
+				payload.Answers[i].SelectedOptions = handlerUtil.AllocatedOptions(answer.Allocation)
+			case utils.MATRIX:
+				payload.Answers[i].SelectedOptions = append([]int{}, answer.RowAnswers...)
+			}
+		}
+	}
+
+	if err := handlerUtil.ValidateSurveyAnswers(*session, payload.Answers); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	surveyEvent := KafkaC.SurveySubmittedEvent{
+		EventID:       primitive.NewObjectID().Hex(),
+		Type:          utils.SURVEY_SUBMITTED_EVENT,
+		SubmissionID:  primitive.NewObjectID().Hex(),
+		SessionID:     payload.SessionID,
+		ParticipantID: payload.ParticipantID,
+		Timestamp:     time.Now(),
+	}
+	if !session.SecretBallot {
+		surveyEvent.IPAddress = handlerUtil.GetIPAddress(r)
+		surveyEvent.UserAgent = r.UserAgent()
+	}
+
+	voteIDs := make(map[string]string)
+	for _, answer := range payload.Answers {
+		voteID := primitive.NewObjectID().Hex()
+		voteIDs[answer.QuestionID] = voteID
+
+		surveyEvent.Answers = append(surveyEvent.Answers, KafkaC.SurveyAnswerEvent{
+			VoteID:          voteID,
+			QuestionID:      answer.QuestionID,
+			SelectedOptions: answer.SelectedOptions,
+			OtherText:       strings.TrimSpace(answer.OtherText),
+			Availability:    answer.Availability,
+			Allocation:      answer.Allocation,
+			RowAnswers:      answer.RowAnswers,
+		})
+	}
+
+	if session.SecretBallot {
+		if err := KafkaC.SealSurveyEvent(&surveyEvent); err != nil {
+			log.Printf("Failed to seal secret ballots: %v", err)
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to process survey")
+			return
+		}
+	}
+
+	if err := KafkaC.ProduceSurveySubmitted(surveyEvent); err != nil {
+		log.Printf("Failed to send survey to Kafka: %v", err)
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to process survey")
+		return
+	}
+
+	nowTime, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+
+	utils.JSONResponse(w, http.StatusAccepted, map[string]interface{}{
+		"message":      "Survey accepted for processing",
+		"submissionId": surveyEvent.SubmissionID,
+		"voteIds":      voteIDs, // questionId -> voteId
+		"sessionId":    payload.SessionID,
+		"timeStamp":    nowTime,
+	})
+}
@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SaveTemplateHandler saves a session layout as a template, either copied from an existing
+// session (sessionId) or given as a session body. Votes, ids and the join code are not kept.
+func SaveTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req models.TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Something wrong with request body format")
+		return
+	}
+	if err := handlerUtil.ValidateTemplateRequest(req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var source models.Session
+	if req.SessionID != "" {
+		sessionID, err := primitive.ObjectIDFromHex(req.SessionID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+			return
+		}
+		session, err := repository.GetSessionByID(sessionID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		source = *session
+	} else {
+		source = *req.Session
+	}
+
+	layout := handlerUtil.SessionLayout(source)
+	if err := handlerUtil.ValidateSessionRequest(layout); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	nowTime, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	template := models.SessionTemplate{
+		ID:          primitive.NewObjectID(),
+		OrganizerId: organizerID,
+		Name:        req.Name,
+		Description: req.Description,
+		Session:     layout,
+		CreatedAt:   nowTime,
+		UpdatedAt:   nowTime,
+	}
+
+	if err := services.SaveTemplate(template); err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to save template")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusCreated, map[string]interface{}{
+		"message":  "Template saved successfully.",
+		"template": template,
+	})
+}
+
+func ListTemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	templates, err := services.ListTemplates(organizerID)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load templates")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"templates": templates,
+	})
+}
+
+func DeleteTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try DELETE !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["templateId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	if err := services.DeleteTemplate(templateID, organizerID); err != nil {
+		if errors.Is(err, services.ErrTemplateNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, "Template not found")
+			return
+		}
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to delete template")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]string{
+		"message": "Template deleted.",
+	})
+}
+
+// CreateSessionFromTemplateHandler starts a new session from a template. The body is optional,
+// {"title": "..."} replaces the template's title (e.g. "Retro sprint 42").
+func CreateSessionFromTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["templateId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	var req struct {
+		Title string `json:"title"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Something wrong with request body format")
+		return
+	}
+
+	template, err := services.GetTemplate(templateID, organizerID)
+	if err != nil {
+		if errors.Is(err, services.ErrTemplateNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, "Template not found")
+			return
+		}
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load template")
+		return
+	}
+
+	session := template.Session
+	if req.Title != "" {
+		session.Title = req.Title
+	}
+	saveSessionFrom(w, session, organizerID)
+}
+
+// AddBankQuestionHandler adds a question with its tags to the organizer's question bank.
+func AddBankQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req models.BankQuestion
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Something wrong with request body format")
+		return
+	}
+
+	tags, err := handlerUtil.NormalizeTags(req.Tags)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	question := handlerUtil.SessionLayout(models.Session{Questions: []models.Question{req.Question}}).Questions[0]
+	if len(question.Type) == 0 {
+		question.Type = utils.SINGLE
+	}
+	if err := handlerUtil.ValidateBankQuestion(question); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	nowTime, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
+	bankQuestion := models.BankQuestion{
+		ID:          primitive.NewObjectID(),
+		OrganizerId: organizerID,
+		Tags:        tags,
+		Question:    question,
+		CreatedAt:   nowTime,
+	}
+
+	if err := services.SaveBankQuestion(bankQuestion); err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to save bank question")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusCreated, map[string]interface{}{
+		"message":  "Question added to the bank.",
+		"question": bankQuestion,
+	})
+}
+
+// ListBankQuestionsHandler lists the organizer's bank questions, ?tag= (repeatable) keeps the ones
+// carrying every given tag.
+func ListBankQuestionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try GET !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	tags, err := handlerUtil.NormalizeTags(r.URL.Query()["tag"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	questions, err := services.ListBankQuestions(organizerID, tags)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load bank questions")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"questions": questions,
+	})
+}
+
+func DeleteBankQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try DELETE !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(mux.Vars(r)["bankQuestionId"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid bank question ID")
+		return
+	}
+
+	if err := services.DeleteBankQuestion(questionID, organizerID); err != nil {
+		if errors.Is(err, services.ErrBankQuestionNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, "Bank question not found")
+			return
+		}
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to delete bank question")
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, map[string]string{
+		"message": "Bank question deleted.",
+	})
+}
+
+// CreateSessionFromBankHandler creates a session from picked bank questions. The body is a new
+// poll (title, settings, optionally its own questions) with bankQuestionIds appended in order.
+func CreateSessionFromBankHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed. Try POST !")
+		return
+	}
+
+	organizerID, err := handlerUtil.OrganizerID(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req models.BankSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Something wrong with request body format")
+		return
+	}
+	if len(req.BankQuestionIDs) == 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "bankQuestionIds is required")
+		return
+	}
+
+	ids := make([]primitive.ObjectID, len(req.BankQuestionIDs))
+	for i, id := range req.BankQuestionIDs {
+		ids[i], err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid bank question ID: "+id)
+			return
+		}
+	}
+
+	bankQuestions, err := services.GetBankQuestions(organizerID, ids)
+	if err != nil {
+		if errors.Is(err, services.ErrBankQuestionNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, "Bank question not found")
+			return
+		}
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load bank questions")
+		return
+	}
+
+	session := handlerUtil.SessionLayout(req.Session)
+	for _, bankQuestion := range bankQuestions {
+		session.Questions = append(session.Questions, bankQuestion.Question)
+	}
+	saveSessionFrom(w, session, organizerID)
+}
+
+// saveSessionFrom stores a session built from a template or the bank the way CreateNewPoll does:
+// same defaults and validation, a fresh join code, and question ids from services.SavePollQuestions.
+func saveSessionFrom(w http.ResponseWriter, session models.Session, organizerID primitive.ObjectID) {
+	session.OrganizerId = organizerID
+	session.JoinCode = ""
+	if err := prepareNewSession(&session); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session.JoinCode = utils.GenerateJoinCode()
+	sessionID, err := services.SavePollQuestions(session)
+	if err != nil {
+		log.Println(err.Error())
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create session")
+		return
+	}
+
+	log.Printf("Session %s created for organizer %s", *sessionID, organizerID.Hex())
+	utils.JSONResponse(w, http.StatusCreated, map[string]interface{}{
+		"message":   "Question Poll saved successfully.",
+		"sessionId": *sessionID,
+		"joinCode":  session.JoinCode,
+	})
+}
@@ -1,9 +1,21 @@
 package utils
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
 	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/storage"
+	"RealTimePoll/internal/utils"
+	"RealTimePoll/pkg/jwt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func GetIPAddress(r *http.Request) string {
@@ -21,11 +33,1066 @@ func ValidateVoteRequest(req models.VoteRequest) error {
     if req.QuestionID == "" {
         return fmt.Errorf("questionId is required")
     }
+    if req.ParticipantID == "" && req.VoterToken == "" {
+        return fmt.Errorf("participantId is required")
+    }
+    if err := validateParticipantID(req.ParticipantID); err != nil {
+        return err
+    }
+    if len(req.SelectedOptions) == 0 && len(req.Availability) == 0 && len(req.Allocation) == 0 && len(req.RowAnswers) == 0 {
+        return fmt.Errorf("selectedOptions cannot be empty")
+    }
+    return nil
+}
+
+// validateParticipantID checks the participant id a vote is stored under. Votes keep it as an
+// ObjectID, so anything else would collapse every participant onto the same nil id.
+func validateParticipantID(participantID string) error {
+    if participantID == "" {
+        return nil
+    }
+    if _, err := primitive.ObjectIDFromHex(participantID); err != nil {
+        return fmt.Errorf("participantId must be a 24 character hex id")
+    }
+    return nil
+}
+
+// QuestionValidationError points at the question of a session definition that failed validation.
+type QuestionValidationError struct {
+    Index   int
+    Message string
+}
+
+func (e *QuestionValidationError) Error() string {
+    return fmt.Sprintf("question %d: %s", e.Index+1, e.Message)
+}
+
+// ApplySessionDefaults fills what a new session gets unless its author set it: poll mode, single
+// choice questions, the default slider range, slot labels and the vote policy of capacity options.
+func ApplySessionDefaults(session *models.Session) {
+    if len(session.Mode) == 0 {
+        session.Mode = utils.POLL_MODE
+    }
+    for i := range session.Questions {
+        if len(session.Questions[i].Type) == 0 {
+            session.Questions[i].Type = utils.SINGLE
+        }
+        if session.Questions[i].Type == utils.PULSE && session.Questions[i].SliderMin == 0 && session.Questions[i].SliderMax == 0 {
+            session.Questions[i].SliderMax = 100
+        }
+        if session.Questions[i].Type == utils.SCHEDULE && len(session.Questions[i].Options) == 0 {
+            for _, slot := range session.Questions[i].Slots {
+                session.Questions[i].Options = append(session.Questions[i].Options, utils.SlotLabel(slot.Start, slot.End, slot.Timezone))
+            }
+        }
+        if session.Questions[i].HasCapacity() && len(session.Questions[i].VotePolicy) == 0 {
+            session.Questions[i].VotePolicy = utils.VOTE_RETRACTABLE
+        }
+        // open/close state is driven by the organizer once the poll runs
+        session.Questions[i].OpenedAt = nil
+        session.Questions[i].ClosedAt = nil
+    }
+}
+
+// ValidateSessionRequest checks a session definition before it is saved.
+func ValidateSessionRequest(session models.Session) error {
+    if session.Title == "" && session.IsQuiz() {
+        return fmt.Errorf("title is required in quiz mode")
+    }
+
+    switch session.Mode {
+    case "", utils.POLL_MODE, utils.QUIZ_MODE:
+    default:
+        return fmt.Errorf("mode must be '%s' or '%s'", utils.POLL_MODE, utils.QUIZ_MODE)
+    }
+
+    if session.SecretBallot && session.IsQuiz() {
+        return fmt.Errorf("secret ballot is not available in quiz mode")
+    }
+
+    // a weight few voters share would point at their ballots.
+    if session.SecretBallot && session.Weighted {
+        return fmt.Errorf("secret ballot is not available in weighted sessions")
+    }
+
+    // the invite token is what proves a voter carries the weight of their roster entry.
+    if session.Weighted && !session.InviteOnly {
+        return fmt.Errorf("weighted sessions must be invite-only")
+    }
+
+    if session.QuorumPercent < 0 || session.QuorumPercent > 100 {
+        return fmt.Errorf("quorumPercent must be between 0 and 100")
+    }
+
+    if err := validateClosingRules(session, session.ClosingRules); err != nil {
+        return fmt.Errorf("closingRules: %v", err)
+    }
+
+    if err := validatePrivacySettings(session.Privacy); err != nil {
+        return fmt.Errorf("privacy: %v", err)
+    }
+
+    if err := validateSessionLocales(session); err != nil {
+        return err
+    }
+
+    if len(session.Questions) == 0 {
+        return fmt.Errorf("at least one question is required")
+    }
+
+    for i, question := range session.Questions {
+        if err := validateQuestionDefinition(session, question); err != nil {
+            return &QuestionValidationError{Index: i, Message: err.Error()}
+        }
+    }
+    return validateBranching(session)
+}
+
+// validateBranching checks display conditions: unique keys, no dangling references and no cycles.
+func validateBranching(session models.Session) error {
+    keys := make(map[string]int)
+    for i, question := range session.Questions {
+        if question.Key == "" {
+            continue
+        }
+        if _, exists := keys[question.Key]; exists {
+            return &QuestionValidationError{Index: i, Message: fmt.Sprintf("key '%s' is used more than once", question.Key)}
+        }
+        keys[question.Key] = i
+    }
+
+    if session.HasBranching() && session.SecretBallot {
+        return fmt.Errorf("showIf needs earlier answers, which secret ballot does not link to participants")
+    }
+
+    for i, question := range session.Questions {
+        for _, condition := range question.ShowIf {
+            source, exists := keys[condition.QuestionKey]
+            if !exists {
+                return &QuestionValidationError{Index: i, Message: fmt.Sprintf("showIf refers to unknown question key '%s'", condition.QuestionKey)}
+            }
+            if source == i {
+                return &QuestionValidationError{Index: i, Message: "showIf cannot refer to the question itself"}
+            }
+            if len(condition.AnyOf) == 0 {
+                return &QuestionValidationError{Index: i, Message: "showIf needs at least one option in anyOf"}
+            }
+            if err := validateOptionIndexes(condition.AnyOf, len(session.Questions[source].Options)); err != nil {
+                return &QuestionValidationError{Index: i, Message: fmt.Sprintf("showIf anyOf: %v", err)}
+            }
+        }
+    }
+
+    // depth-first search over "depends on" edges, a question met again while on the stack closes a cycle.
+    const (
+        unvisited = iota
+        onStack
+        done
+    )
+    state := make([]int, len(session.Questions))
+    var visit func(i int) error
+    visit = func(i int) error {
+        state[i] = onStack
+        for _, condition := range session.Questions[i].ShowIf {
+            source := keys[condition.QuestionKey]
+            switch state[source] {
+            case onStack:
+                return &QuestionValidationError{Index: i, Message: fmt.Sprintf("showIf creates a cycle through question key '%s'", condition.QuestionKey)}
+            case unvisited:
+                if err := visit(source); err != nil {
+                    return err
+                }
+            }
+        }
+        state[i] = done
+        return nil
+    }
+
+    for i := range session.Questions {
+        if state[i] == unvisited {
+            if err := visit(i); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+func validateQuestionDefinition(session models.Session, question models.Question) error {
+    if question.Text == "" {
+        return fmt.Errorf("text is required")
+    }
+
+    switch question.Type {
+    case utils.SINGLE, utils.MULTIPLE, utils.SCHEDULE, utils.ALLOCATION, utils.MATRIX:
+    case utils.PULSE:
+        return validatePulseDefinition(session, question)
+    default:
+        return fmt.Errorf("type must be '%s', '%s', '%s', '%s', '%s' or '%s'", utils.SINGLE, utils.MULTIPLE, utils.SCHEDULE, utils.ALLOCATION, utils.MATRIX, utils.PULSE)
+    }
+
+    if question.SliderMin != 0 || question.SliderMax != 0 {
+        return fmt.Errorf("sliderMin and sliderMax are only allowed on '%s' questions", utils.PULSE)
+    }
+
+    if len(question.Options) < 2 {
+        return fmt.Errorf("at least two options are required")
+    }
+
+    if len(question.CorrectOptions) > 0 && !session.IsQuiz() {
+        return fmt.Errorf("correctOptions are only allowed in quiz mode")
+    }
+    if question.Type == utils.SINGLE && len(question.CorrectOptions) > 1 {
+        return fmt.Errorf("single choice questions can have only one correct option")
+    }
+    if err := validateOptionIndexes(question.CorrectOptions, len(question.Options)); err != nil {
+        return fmt.Errorf("correctOptions: %v", err)
+    }
+
+    switch question.VotePolicy {
+    case "", utils.VOTE_LOCKED, utils.VOTE_CHANGEABLE, utils.VOTE_RETRACTABLE:
+    default:
+        return fmt.Errorf("votePolicy must be '%s', '%s' or '%s'", utils.VOTE_LOCKED, utils.VOTE_CHANGEABLE, utils.VOTE_RETRACTABLE)
+    }
+
+    if err := validateOptionImages(question); err != nil {
+        return fmt.Errorf("optionImages: %v", err)
+    }
+
+    if err := validateOptionIndexes(question.FreeTextOptions, len(question.Options)); err != nil {
+        return fmt.Errorf("freeTextOptions: %v", err)
+    }
+
+    if question.PinLastOption && !question.ShuffleOptions {
+        return fmt.Errorf("pinLastOption needs shuffleOptions")
+    }
+
+    if err := validateSchedule(session, question); err != nil {
+        return err
+    }
+
+    if err := validateAllocationDefinition(session, question); err != nil {
+        return err
+    }
+
+    if err := validateMatrixDefinition(session, question); err != nil {
+        return err
+    }
+
+    if err := validateOptionCapacities(session, question); err != nil {
+        return fmt.Errorf("optionCapacities: %v", err)
+    }
+
+    switch question.ResultsVisibility {
+    case "", utils.RESULTS_ALWAYS, utils.RESULTS_AFTER_VOTE, utils.RESULTS_AFTER_CLOSE, utils.RESULTS_ORGANIZER_ONLY:
+    default:
+        return fmt.Errorf("resultsVisibility must be '%s', '%s', '%s' or '%s'", utils.RESULTS_ALWAYS, utils.RESULTS_AFTER_VOTE, utils.RESULTS_AFTER_CLOSE, utils.RESULTS_ORGANIZER_ONLY)
+    }
+
+    // changing a secret ballot would need a link back to the voter.
+    if session.SecretBallot && question.VotePolicy != "" && question.VotePolicy != utils.VOTE_LOCKED {
+        return fmt.Errorf("secret ballot questions must use the '%s' vote policy", utils.VOTE_LOCKED)
+    }
+
+    if err := validateClosingRules(session, question.ClosingRules); err != nil {
+        return fmt.Errorf("closingRules: %v", err)
+    }
+
+    if question.Points < 0 || question.TimeLimitSeconds < 0 {
+        return fmt.Errorf("points and timeLimitSeconds cannot be negative")
+    }
+
+    if err := validateTranslations(session, question); err != nil {
+        return fmt.Errorf("translations: %v", err)
+    }
+    return nil
+}
+
+func validateSessionLocales(session models.Session) error {
+    if session.DefaultLocale != "" && !utils.ValidLocale(session.DefaultLocale) {
+        return fmt.Errorf("defaultLocale '%s' is not a valid locale tag", session.DefaultLocale)
+    }
+    for locale, title := range session.TitleTranslations {
+        if err := validateTranslationLocale(session, locale); err != nil {
+            return fmt.Errorf("titleTranslations: %v", err)
+        }
+        if strings.TrimSpace(title) == "" {
+            return fmt.Errorf("titleTranslations: '%s' is empty", locale)
+        }
+    }
+    return nil
+}
+
+// validateTranslations checks that every translation lines up with the question's options and rows,
+// so that a vote means the same option whatever language it was cast in.
+func validateTranslations(session models.Session, question models.Question) error {
+    for locale, translation := range question.Translations {
+        if err := validateTranslationLocale(session, locale); err != nil {
+            return err
+        }
+        if strings.TrimSpace(translation.Text) == "" {
+            return fmt.Errorf("'%s': text is required", locale)
+        }
+        // options and rows may be left out, e.g. for schedule slot labels, and then stay as they are.
+        if len(translation.Options) > 0 && len(translation.Options) != len(question.Options) {
+            return fmt.Errorf("'%s': %d options given, the question has %d", locale, len(translation.Options), len(question.Options))
+        }
+        if len(translation.Rows) > 0 && len(translation.Rows) != len(question.Rows) {
+            return fmt.Errorf("'%s': %d rows given, the question has %d", locale, len(translation.Rows), len(question.Rows))
+        }
+    }
+    return nil
+}
+
+func validateTranslationLocale(session models.Session, locale string) error {
+    if session.DefaultLocale == "" {
+        return fmt.Errorf("defaultLocale is required with translations")
+    }
+    if !utils.ValidLocale(locale) {
+        return fmt.Errorf("'%s' is not a valid locale tag", locale)
+    }
+    if strings.EqualFold(locale, session.DefaultLocale) {
+        return fmt.Errorf("'%s' is the default locale, its texts are the question's own", locale)
+    }
+    return nil
+}
+
+func validateOptionImages(question models.Question) error {
+    if len(question.OptionImages) == 0 {
+        return nil
+    }
+    if len(question.OptionImages) != len(question.Options) {
+        return fmt.Errorf("one entry per option is required, with an empty id for options without image")
+    }
+    for i, optionImage := range question.OptionImages {
+        if optionImage.ID != "" && !storage.ValidImageID(optionImage.ID) {
+            return fmt.Errorf("option %d: '%s' is not an uploaded image id", i+1, optionImage.ID)
+        }
+    }
+    return nil
+}
+
+func validateSchedule(session models.Session, question models.Question) error {
+    if question.Type != utils.SCHEDULE {
+        if len(question.Slots) > 0 {
+            return fmt.Errorf("slots are only allowed on '%s' questions", utils.SCHEDULE)
+        }
+        return nil
+    }
+
+    if len(question.Slots) != len(question.Options) {
+        return fmt.Errorf("one slot per option is required")
+    }
+    for i, slot := range question.Slots {
+        if slot.Start.IsZero() || !slot.End.After(slot.Start) {
+            return fmt.Errorf("slot %d: end must be after start", i+1)
+        }
+        if _, err := utils.SlotLocation(slot.Timezone); err != nil {
+            return fmt.Errorf("slot %d: unknown timezone '%s'", i+1, slot.Timezone)
+        }
+    }
+
+    // availability is given per slot in the slot order.
+    switch {
+    case session.IsQuiz():
+        return fmt.Errorf("schedule questions are not available in quiz mode")
+    case session.HasPrivacy():
+        return fmt.Errorf("schedule questions are not available with result privacy")
+    case question.ShuffleOptions:
+        return fmt.Errorf("schedule questions cannot shuffle options")
+    case len(question.FreeTextOptions) > 0:
+        return fmt.Errorf("schedule questions cannot have free-text options")
+    case len(question.OptionCapacities) > 0:
+        return fmt.Errorf("schedule questions cannot have option capacities")
+    }
+    return nil
+}
+
+func validateAllocationDefinition(session models.Session, question models.Question) error {
+    if question.Type != utils.ALLOCATION {
+        if question.Budget != 0 || len(question.AllocationCaps) > 0 || question.BudgetUnit != "" {
+            return fmt.Errorf("budget and allocationCaps are only allowed on '%s' questions", utils.ALLOCATION)
+        }
+        return nil
+    }
+
+    if question.Budget <= 0 {
+        return fmt.Errorf("budget must be positive")
+    }
+    if len(question.AllocationCaps) > 0 && len(question.AllocationCaps) != len(question.Options) {
+        return fmt.Errorf("allocationCaps: one cap per option is required, 0 for no cap")
+    }
+    for _, limit := range question.AllocationCaps {
+        if limit < 0 || limit > question.Budget {
+            return fmt.Errorf("allocationCaps: caps must be between 0 and the budget")
+        }
+    }
+
+    // points are given per option in the canonical order.
+    switch {
+    case session.IsQuiz():
+        return fmt.Errorf("allocation questions are not available in quiz mode")
+    case session.HasPrivacy():
+        return fmt.Errorf("allocation questions are not available with result privacy")
+    case question.ShuffleOptions:
+        return fmt.Errorf("allocation questions cannot shuffle options")
+    case len(question.FreeTextOptions) > 0:
+        return fmt.Errorf("allocation questions cannot have free-text options")
+    case len(question.OptionCapacities) > 0:
+        return fmt.Errorf("allocation questions cannot have option capacities")
+    }
+    return nil
+}
+
+func validateMatrixDefinition(session models.Session, question models.Question) error {
+    if question.Type != utils.MATRIX {
+        if len(question.Rows) > 0 {
+            return fmt.Errorf("rows are only allowed on '%s' questions", utils.MATRIX)
+        }
+        return nil
+    }
+
+    if len(question.Rows) == 0 {
+        return fmt.Errorf("at least one row is required")
+    }
+    for i, row := range question.Rows {
+        if strings.TrimSpace(row) == "" {
+            return fmt.Errorf("row %d: text is required", i+1)
+        }
+    }
+
+    // every row is answered on the same columns, in the canonical order.
+    switch {
+    case session.IsQuiz():
+        return fmt.Errorf("matrix questions are not available in quiz mode")
+    case session.HasPrivacy():
+        return fmt.Errorf("matrix questions are not available with result privacy")
+    case question.ShuffleOptions:
+        return fmt.Errorf("matrix questions cannot shuffle options")
+    case len(question.FreeTextOptions) > 0:
+        return fmt.Errorf("matrix questions cannot have free-text options")
+    case len(question.OptionCapacities) > 0:
+        return fmt.Errorf("matrix questions cannot have option capacities")
+    }
+    return nil
+}
+
+// pulse questions have no options, participants move a slider between sliderMin and sliderMax.
+func validatePulseDefinition(session models.Session, question models.Question) error {
+    if len(question.Options) > 0 {
+        return fmt.Errorf("pulse questions take no options")
+    }
+    if question.SliderMax <= question.SliderMin {
+        return fmt.Errorf("sliderMax must be greater than sliderMin")
+    }
+    if session.IsQuiz() {
+        return fmt.Errorf("pulse questions are not available in quiz mode")
+    }
+    if question.Required || question.ClosingRules != nil || len(question.ShowIf) > 0 {
+        return fmt.Errorf("pulse questions cannot be required, closed by rules or shown conditionally")
+    }
+    return nil
+}
+
+func validateOptionCapacities(session models.Session, question models.Question) error {
+    if len(question.OptionCapacities) == 0 {
+        if question.Waitlist {
+            return fmt.Errorf("waitlist needs at least one limited option")
+        }
+        return nil
+    }
+    if len(question.OptionCapacities) != len(question.Options) {
+        return fmt.Errorf("one capacity per option is required, 0 for unlimited")
+    }
+    for _, capacity := range question.OptionCapacities {
+        if capacity < 0 {
+            return fmt.Errorf("capacity cannot be negative")
+        }
+    }
+    if session.SecretBallot {
+        return fmt.Errorf("not available with secret ballot, seats are released by the voter")
+    }
+    // participants free their seat by retracting.
+    if question.HasCapacity() && question.VotePolicy != utils.VOTE_RETRACTABLE {
+        return fmt.Errorf("capacity-limited questions must use the '%s' vote policy", utils.VOTE_RETRACTABLE)
+    }
+    return nil
+}
+
+func validatePrivacySettings(privacy *models.PrivacySettings) error {
+    if privacy == nil {
+        return nil
+    }
+    if privacy.MinCount < 0 {
+        return fmt.Errorf("minCount cannot be negative")
+    }
+    if privacy.RoundPercentTo < 0 || privacy.RoundPercentTo > 100 {
+        return fmt.Errorf("roundPercentTo must be between 0 and 100")
+    }
+    if privacy.Epsilon < 0 {
+        return fmt.Errorf("epsilon cannot be negative")
+    }
+    if privacy.Epsilon > 0 && privacy.EpsilonBudget < privacy.Epsilon {
+        return fmt.Errorf("epsilonBudget must cover at least one release of epsilon")
+    }
+    return nil
+}
+
+func validateClosingRules(session models.Session, rules *models.ClosingRules) error {
+    if rules == nil {
+        return nil
+    }
+    if rules.OnQuorum && session.QuorumPercent <= 0 {
+        return fmt.Errorf("onQuorum needs the session quorumPercent")
+    }
+    if rules.AllRosterVoted && !session.HasRoster() {
+        return fmt.Errorf("allRosterVoted needs a roster (inviteOnly, weighted or quorumPercent)")
+    }
+    if rules.MaxVotes < 0 {
+        return fmt.Errorf("maxVotes cannot be negative")
+    }
+    return nil
+}
+
+func validateOptionIndexes(indexes []int, optionCount int) error {
+    seen := make(map[int]bool)
+    for _, index := range indexes {
+        if index < 0 || index >= optionCount {
+            return fmt.Errorf("option index %d out of range", index)
+        }
+        if seen[index] {
+            return fmt.Errorf("option index %d repeated", index)
+        }
+        seen[index] = true
+    }
+    return nil
+}
+
+func ValidateAudienceQuestionRequest(req models.AudienceQuestionRequest) error {
     if req.ParticipantID == "" {
         return fmt.Errorf("participantId is required")
     }
-    if len(req.SelectedOptions) == 0 {
+    if len(strings.TrimSpace(req.Text)) == 0 {
+        return fmt.Errorf("text is required")
+    }
+    if len(req.Text) > utils.QNA_MAX_TEXT_LENGTH {
+        return fmt.Errorf("text cannot be longer than %d characters", utils.QNA_MAX_TEXT_LENGTH)
+    }
+    return nil
+}
+
+// ValidateSelectedOptions checks an answer against the question it is given for.
+func ValidateSelectedOptions(question models.Question, selected []int) error {
+    if question.Type == utils.PULSE {
+        return fmt.Errorf("pulse questions are answered with the live slider")
+    }
+    // a schedule answer of "no" on every slot selects nothing.
+    if question.Type == utils.SCHEDULE {
+        return validateOptionIndexes(selected, len(question.Options))
+    }
+    // matrix selections are the row answers, where columns repeat, see ValidateRowAnswers.
+    if question.Type == utils.MATRIX {
+        return nil
+    }
+    if len(selected) == 0 {
         return fmt.Errorf("selectedOptions cannot be empty")
     }
+    if question.Type == utils.SINGLE && len(selected) > 1 {
+        return fmt.Errorf("only one option can be selected")
+    }
+    return validateOptionIndexes(selected, len(question.Options))
+}
+
+// ValidateOtherText checks the free text of an answer: it is required when a free-text option is
+// selected and not accepted otherwise.
+func ValidateOtherText(question models.Question, selected []int, otherText string) error {
+    freeText := 0
+    for _, option := range selected {
+        for _, allowed := range question.FreeTextOptions {
+            if option == allowed {
+                freeText++
+            }
+        }
+    }
+
+    text := strings.TrimSpace(otherText)
+    switch {
+    case freeText == 0 && len(otherText) > 0:
+        return fmt.Errorf("otherText is only accepted with a free-text option")
+    case freeText > 1:
+        return fmt.Errorf("only one free-text option can be selected")
+    case freeText == 0:
+        return nil
+    case len(text) == 0:
+        return fmt.Errorf("otherText is required for the selected option")
+    case utf8.RuneCountInString(text) > utils.OTHER_TEXT_MAX_LENGTH:
+        return fmt.Errorf("otherText cannot be longer than %d characters", utils.OTHER_TEXT_MAX_LENGTH)
+    case !utf8.ValidString(text):
+        return fmt.Errorf("otherText must be valid UTF-8")
+    }
+
+    for _, r := range text {
+        if unicode.IsControl(r) || r == '<' || r == '>' {
+            return fmt.Errorf("otherText cannot contain control characters or markup")
+        }
+    }
+    if len(utils.NormalizeFreeText(text)) == 0 {
+        return fmt.Errorf("otherText must contain letters or digits")
+    }
+    return nil
+}
+
+// ValidateAvailability checks the per-slot answers of a schedule question, which take no other kind of answer.
+func ValidateAvailability(question models.Question, availability []string) error {
+    if question.Type != utils.SCHEDULE {
+        if len(availability) > 0 {
+            return fmt.Errorf("availability is only accepted on '%s' questions", utils.SCHEDULE)
+        }
+        return nil
+    }
+
+    if len(availability) != len(question.Slots) {
+        return fmt.Errorf("availability must answer each of the %d slots", len(question.Slots))
+    }
+    for i, answer := range availability {
+        switch answer {
+        case utils.AVAILABILITY_YES, utils.AVAILABILITY_IF_NEED_BE, utils.AVAILABILITY_NO:
+        default:
+            return fmt.Errorf("slot %d: availability must be '%s', '%s' or '%s'", i+1, utils.AVAILABILITY_YES, utils.AVAILABILITY_IF_NEED_BE, utils.AVAILABILITY_NO)
+        }
+    }
+    return nil
+}
+
+// AvailableSlots returns the slots a participant can make, counted as the selected options of the vote.
+func AvailableSlots(availability []string) []int {
+    selected := []int{}
+    for i, answer := range availability {
+        if answer == utils.AVAILABILITY_YES || answer == utils.AVAILABILITY_IF_NEED_BE {
+            selected = append(selected, i)
+        }
+    }
+    return selected
+}
+
+// ValidateAllocation checks the points of an allocation answer: one non-negative amount per option,
+// within the option caps, at least one point and at most the budget in total.
+func ValidateAllocation(question models.Question, allocation []int) error {
+    if question.Type != utils.ALLOCATION {
+        if len(allocation) > 0 {
+            return fmt.Errorf("allocation is only accepted on '%s' questions", utils.ALLOCATION)
+        }
+        return nil
+    }
+
+    if len(allocation) != len(question.Options) {
+        return fmt.Errorf("allocation must give points to each of the %d options, 0 included", len(question.Options))
+    }
+    total := 0
+    for i, points := range allocation {
+        if points < 0 {
+            return fmt.Errorf("option %d: points cannot be negative", i+1)
+        }
+        if i < len(question.AllocationCaps) && question.AllocationCaps[i] > 0 && points > question.AllocationCaps[i] {
+            return fmt.Errorf("option %d: at most %d points can be given", i+1, question.AllocationCaps[i])
+        }
+        total += points
+    }
+    if total == 0 {
+        return fmt.Errorf("allocation must give at least one point")
+    }
+    if total > question.Budget {
+        return fmt.Errorf("allocation of %d points exceeds the budget of %d", total, question.Budget)
+    }
+    return nil
+}
+
+// AllocatedOptions returns the options given points, counted as the selected options of the vote.
+func AllocatedOptions(allocation []int) []int {
+    selected := []int{}
+    for i, points := range allocation {
+        if points > 0 {
+            selected = append(selected, i)
+        }
+    }
+    return selected
+}
+
+// ValidateRowAnswers checks the answers of a matrix question: one column per row.
+func ValidateRowAnswers(question models.Question, rowAnswers []int) error {
+    if question.Type != utils.MATRIX {
+        if len(rowAnswers) > 0 {
+            return fmt.Errorf("rowAnswers are only accepted on '%s' questions", utils.MATRIX)
+        }
+        return nil
+    }
+
+    if len(rowAnswers) != len(question.Rows) {
+        return fmt.Errorf("rowAnswers must answer each of the %d rows", len(question.Rows))
+    }
+    for i, column := range rowAnswers {
+        if column < 0 || column >= len(question.Options) {
+            return fmt.Errorf("row %d: column %d out of range", i+1, column)
+        }
+    }
+    return nil
+}
+
+func ValidateSurveyRequest(req models.SurveyRequest) error {
+    if req.SessionID == "" {
+        return fmt.Errorf("sessionId is required")
+    }
+    if req.ParticipantID == "" && req.VoterToken == "" {
+        return fmt.Errorf("participantId is required")
+    }
+    if err := validateParticipantID(req.ParticipantID); err != nil {
+        return err
+    }
+    if len(req.Answers) == 0 {
+        return fmt.Errorf("answers cannot be empty")
+    }
+    return nil
+}
+
+// ValidateSurveyAnswers validates all answers of a submission together: every question must
+// exist and be open, be answered at most once, and every required question must be answered.
+func ValidateSurveyAnswers(session models.Session, answers []models.SurveyAnswer) error {
+    answered := make(map[string]bool)
+
+    for i, answer := range answers {
+        questionID, err := primitive.ObjectIDFromHex(answer.QuestionID)
+        if err != nil {
+            return fmt.Errorf("answer %d: invalid question ID", i+1)
+        }
+
+        question, found := session.FindQuestion(questionID)
+        if !found {
+            return fmt.Errorf("answer %d: question not found in session", i+1)
+        }
+        if question.ClosedAt != nil {
+            return fmt.Errorf("answer %d: question is closed", i+1)
+        }
+        if session.IsQuiz() && question.OpenedAt == nil {
+            return fmt.Errorf("answer %d: question is not open", i+1)
+        }
+        if answered[questionID.Hex()] {
+            return fmt.Errorf("answer %d: question answered more than once", i+1)
+        }
+        answered[questionID.Hex()] = true
+
+        if err := ValidateAvailability(question, answer.Availability); err != nil {
+            return fmt.Errorf("answer %d: %v", i+1, err)
+        }
+        if err := ValidateAllocation(question, answer.Allocation); err != nil {
+            return fmt.Errorf("answer %d: %v", i+1, err)
+        }
+        if err := ValidateRowAnswers(question, answer.RowAnswers); err != nil {
+            return fmt.Errorf("answer %d: %v", i+1, err)
+        }
+        if err := ValidateSelectedOptions(question, answer.SelectedOptions); err != nil {
+            return fmt.Errorf("answer %d: %v", i+1, err)
+        }
+        if err := ValidateOtherText(question, answer.SelectedOptions, answer.OtherText); err != nil {
+            return fmt.Errorf("answer %d: %v", i+1, err)
+        }
+    }
+
+    // a required question hidden by the submitted answers does not have to be answered.
+    submitted := make(models.ParticipantAnswers)
+    for _, answer := range answers {
+        questionID, _ := primitive.ObjectIDFromHex(answer.QuestionID)
+        submitted[questionID] = answer.SelectedOptions
+    }
+
+    for _, question := range session.Questions {
+        if !session.QuestionVisible(question, submitted) {
+            continue
+        }
+        // quiz questions not opened yet cannot be answered.
+        if session.IsQuiz() && question.OpenedAt == nil {
+            continue
+        }
+        if question.Required && question.ClosedAt == nil && !answered[question.ID.Hex()] {
+            return fmt.Errorf("question '%s' is required", question.Text)
+        }
+    }
+    return nil
+}
+
+func ValidateRetractVoteRequest(req models.RetractVoteRequest) error {
+    if req.SessionID == "" {
+        return fmt.Errorf("sessionId is required")
+    }
+    if req.QuestionID == "" {
+        return fmt.Errorf("questionId is required")
+    }
+    if req.ParticipantID == "" && req.VoterToken == "" {
+        return fmt.Errorf("participantId is required")
+    }
+    if err := validateParticipantID(req.ParticipantID); err != nil {
+        return err
+    }
+    return nil
+}
+
+// ValidateRosterEntries checks an uploaded roster. Every voter needs an email or external ID,
+// identifiers must be unique and weights positive (missing weights default to 1).
+func ValidateRosterEntries(entries []models.RosterEntry) error {
+    if len(entries) == 0 {
+        return fmt.Errorf("roster cannot be empty")
+    }
+
+    seen := make(map[string]bool)
+    for i := range entries {
+        entry := &entries[i]
+        entry.Email = strings.ToLower(strings.TrimSpace(entry.Email))
+        entry.ExternalID = strings.TrimSpace(entry.ExternalID)
+
+        if entry.Email == "" && entry.ExternalID == "" {
+            return fmt.Errorf("entry %d: email or externalId is required", i+1)
+        }
+
+        for _, key := range []string{"email:" + entry.Email, "external:" + entry.ExternalID} {
+            if key == "email:" || key == "external:" {
+                continue
+            }
+            if seen[key] {
+                return fmt.Errorf("entry %d: voter listed more than once", i+1)
+            }
+            seen[key] = true
+        }
+
+        if entry.Weight < 0 {
+            return fmt.Errorf("entry %d: weight cannot be negative", i+1)
+        }
+        if entry.Weight == 0 {
+            entry.Weight = 1
+        }
+    }
+    return nil
+}
+
+// ParseRosterCSV reads a roster from CSV. The header row names the columns: email,
+// externalId (or external_id), name and weight; only one of email/externalId is needed.
+func ParseRosterCSV(reader io.Reader) ([]models.RosterEntry, error) {
+    csvReader := csv.NewReader(reader)
+    csvReader.TrimLeadingSpace = true
+
+    header, err := csvReader.Read()
+    if err != nil {
+        return nil, fmt.Errorf("line 1: missing header row")
+    }
+
+    columns := make(map[string]int)
+    for i, name := range header {
+        name = strings.ToLower(strings.TrimSpace(name))
+        name = strings.ReplaceAll(name, "_", "")
+        columns[name] = i
+    }
+
+    _, hasEmail := columns["email"]
+    _, hasExternalID := columns["externalid"]
+    if !hasEmail && !hasExternalID {
+        return nil, fmt.Errorf("line 1: header needs an email or externalId column")
+    }
+
+    value := func(record []string, column string) string {
+        if i, ok := columns[column]; ok && i < len(record) {
+            return strings.TrimSpace(record[i])
+        }
+        return ""
+    }
+
+    entries := []models.RosterEntry{}
+    for line := 2; ; line++ {
+        record, err := csvReader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %v", line, err)
+        }
+
+        entry := models.RosterEntry{
+            Email:      value(record, "email"),
+            ExternalID: value(record, "externalid"),
+            Name:       value(record, "name"),
+        }
+
+        if weight := value(record, "weight"); weight != "" {
+            entry.Weight, err = strconv.ParseFloat(weight, 64)
+            if err != nil {
+                return nil, fmt.Errorf("line %d: weight '%s' is not a number", line, weight)
+            }
+        }
+        entries = append(entries, entry)
+    }
+    return entries, nil
+}
+
+// OrganizerID returns the organizer of a request that passed the jwt middleware.
+func OrganizerID(r *http.Request) (primitive.ObjectID, error) {
+    claims, ok := r.Context().Value("organizerClaims").(*jwt.Claims)
+    if !ok {
+        return primitive.NilObjectID, fmt.Errorf("organizer token is required")
+    }
+    return primitive.ObjectIDFromHex(claims.OrganizerID)
+}
+
+// CheckSessionOwner fails unless the organizer of the request created the session.
+func CheckSessionOwner(r *http.Request, session models.Session) error {
+    organizerID, err := OrganizerID(r)
+    if err != nil {
+        return err
+    }
+    if session.OrganizerId != organizerID {
+        return fmt.Errorf("session belongs to another organizer")
+    }
     return nil
-}
\ No newline at end of file
+}
+
+// SessionLayout strips a session down to what a template keeps: settings and questions, without
+// ids, join code, status or the open/close state of its questions.
+func SessionLayout(session models.Session) models.Session {
+    layout := session
+    layout.ID = primitive.NilObjectID
+    layout.OrganizerId = primitive.NilObjectID
+    layout.JoinCode = ""
+    layout.Status = ""
+    layout.Locale = ""
+    layout.CreatedAt = time.Time{}
+    layout.UpdatedAt = time.Time{}
+
+    layout.Questions = make([]models.Question, len(session.Questions))
+    for i, question := range session.Questions {
+        question.ID = primitive.NilObjectID
+        question.OpenedAt = nil
+        question.ClosedAt = nil
+        question.OptionOrder = nil
+        layout.Questions[i] = question
+    }
+    return layout
+}
+
+func ValidateTemplateRequest(req models.TemplateRequest) error {
+    if strings.TrimSpace(req.Name) == "" {
+        return fmt.Errorf("name is required")
+    }
+    if (req.SessionID == "") == (req.Session == nil) {
+        return fmt.Errorf("either sessionId or session is required")
+    }
+    return nil
+}
+
+// ValidateBankQuestion checks a question on its own, as it would be checked in a new session.
+// Display conditions and translations depend on the session and are not kept in the bank.
+func ValidateBankQuestion(question models.Question) error {
+    if len(question.ShowIf) > 0 || question.Key != "" {
+        return fmt.Errorf("key and showIf are not kept in the question bank")
+    }
+    if len(question.Translations) > 0 {
+        return fmt.Errorf("translations are not kept in the question bank")
+    }
+
+    session := models.Session{Mode: utils.POLL_MODE}
+    if len(question.CorrectOptions) > 0 || question.Points > 0 {
+        session.Mode = utils.QUIZ_MODE
+    }
+    return validateQuestionDefinition(session, question)
+}
+
+// NormalizeTags lower-cases and trims tags and drops duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+    seen := map[string]bool{}
+    normalized := []string{}
+    for _, tag := range tags {
+        tag = strings.ToLower(strings.TrimSpace(tag))
+        if tag == "" || seen[tag] {
+            continue
+        }
+        if utf8.RuneCountInString(tag) > 40 {
+            return nil, fmt.Errorf("tag '%s' is longer than 40 characters", tag)
+        }
+        seen[tag] = true
+        normalized = append(normalized, tag)
+    }
+    if len(normalized) > 20 {
+        return nil, fmt.Errorf("at most 20 tags are allowed")
+    }
+    return normalized, nil
+}
+
+// ParticipantSessionView is the session as one participant sees it: answer metadata stripped,
+// and questions and options in the participant's shuffled order when the session asks for it.
+func ParticipantSessionView(session models.Session, participantID string) models.Session {
+    view := session.ParticipantView()
+    for i, question := range view.Questions {
+        view.Questions[i] = ParticipantQuestionView(session, question, participantID)
+    }
+    if participantID == "" {
+        return view
+    }
+
+    if session.ShuffleQuestions {
+        order := utils.Permutation(len(view.Questions), session.ID.Hex(), participantID)
+        shuffled := make([]models.Question, len(view.Questions))
+        for position, index := range order {
+            shuffled[position] = view.Questions[index]
+        }
+        view.Questions = shuffled
+    }
+    return view
+}
+
+// ParticipantQuestionView returns the question with its options in the participant's order.
+func ParticipantQuestionView(session models.Session, question models.Question, participantID string) models.Question {
+    view := question.ParticipantView()
+    view.OptionImages = storage.ResolveOptionImages(storage.Default(), question.OptionImages)
+    if !question.ShuffleOptions || participantID == "" {
+        return view
+    }
+
+    order := utils.OptionOrder(len(question.Options), question.PinLastOption, session.ID.Hex(), question.ID.Hex(), participantID)
+    view.Options = make([]string, len(order))
+    for position, index := range order {
+        view.Options[position] = question.Options[index]
+    }
+    if len(view.OptionImages) == len(order) {
+        images := make([]models.OptionImage, len(order))
+        for position, index := range order {
+            images[position] = view.OptionImages[index]
+        }
+        view.OptionImages = images
+    }
+    // free-text options are displayed positions too, so the "please specify" box follows its option.
+    if len(question.FreeTextOptions) > 0 {
+        positions := make([]int, len(order))
+        for position, index := range order {
+            positions[index] = position
+        }
+        view.FreeTextOptions = make([]int, 0, len(question.FreeTextOptions))
+        for _, index := range question.FreeTextOptions {
+            if index >= 0 && index < len(positions) {
+                view.FreeTextOptions = append(view.FreeTextOptions, positions[index])
+            }
+        }
+    }
+    view.OptionOrder = order
+    return view
+}
+
+// RequestLocale picks the session locale for a request: ?locale= first, then Accept-Language.
+// An empty result means the default texts.
+func RequestLocale(session models.Session, r *http.Request) string {
+    return utils.NegotiateLocale(session.Locales(), r.URL.Query().Get("locale"), r.Header.Get("Accept-Language"))
+}
+
+// CanonicalSelection maps the option positions a participant picked on their shuffled view back
+// to the canonical option indices stored with the vote.
+func CanonicalSelection(session models.Session, question models.Question, participantID string, selected []int) []int {
+    if !question.ShuffleOptions {
+        return selected
+    }
+    order := utils.OptionOrder(len(question.Options), question.PinLastOption, session.ID.Hex(), question.ID.Hex(), participantID)
+    return utils.ToCanonicalOptions(order, selected)
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+    "RealTimePoll/internal/models"
+
+    "reflect"
+    "sort"
+    "testing"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCanonicalSelectionMatchesParticipantView(t *testing.T) {
+    session := models.Session{ID: primitive.NewObjectID()}
+    options := []string{"Red", "Green", "Blue", "Yellow", "None of the above"}
+
+    tests := []struct {
+        name     string
+        question models.Question
+    }{
+        {"not shuffled", models.Question{ID: primitive.NewObjectID(), Options: options}},
+        {"shuffled", models.Question{ID: primitive.NewObjectID(), Options: options, ShuffleOptions: true}},
+        {"shuffled with last pinned", models.Question{ID: primitive.NewObjectID(), Options: options, ShuffleOptions: true, PinLastOption: true}},
+        {"shuffled with free-text options", models.Question{ID: primitive.NewObjectID(), Options: options, ShuffleOptions: true, FreeTextOptions: []int{1, 3}}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            participantID := primitive.NewObjectID().Hex()
+            view := ParticipantQuestionView(session, tt.question, participantID)
+            if len(view.Options) != len(options) {
+                t.Fatalf("view has %d options, want %d", len(view.Options), len(options))
+            }
+            if tt.question.PinLastOption && view.Options[len(options)-1] != "None of the above" {
+                t.Errorf("pinned option moved: %v", view.Options)
+            }
+
+            // whatever position the participant picks, the stored index names the same option.
+            for position, text := range view.Options {
+                canonical := CanonicalSelection(session, tt.question, participantID, []int{position})
+                if got := options[canonical[0]]; got != text {
+                    t.Errorf("position %d shows %q but is stored as %q", position, text, got)
+                }
+            }
+
+            // the free-text positions point at the same options as the canonical indices.
+            freeText := []string{}
+            for _, position := range view.FreeTextOptions {
+                freeText = append(freeText, view.Options[position])
+            }
+            wantFreeText := []string{}
+            for _, index := range tt.question.FreeTextOptions {
+                wantFreeText = append(wantFreeText, options[index])
+            }
+            sort.Strings(freeText)
+            sort.Strings(wantFreeText)
+            if !reflect.DeepEqual(freeText, wantFreeText) {
+                t.Errorf("free-text options show %v, want %v", freeText, wantFreeText)
+            }
+            if len(view.FreeTextOptions) > 0 {
+                canonical := CanonicalSelection(session, tt.question, participantID, view.FreeTextOptions[:1])
+                if err := ValidateOtherText(tt.question, canonical, "something else"); err != nil {
+                    t.Errorf("other text on a displayed free-text option rejected: %v", err)
+                }
+            }
+
+            again := ParticipantQuestionView(session, tt.question, participantID)
+            if !reflect.DeepEqual(view.Options, again.Options) {
+                t.Errorf("order changed between views: %v then %v", view.Options, again.Options)
+            }
+        })
+    }
+}
+
+func TestParticipantSessionViewShufflesQuestions(t *testing.T) {
+    questions := []models.Question{}
+    for i := 0; i < 6; i++ {
+        questions = append(questions, models.Question{ID: primitive.NewObjectID(), Options: []string{"a", "b"}})
+    }
+
+    tests := []struct {
+        name          string
+        shuffle       bool
+        participantID string
+    }{
+        {"not shuffled", false, primitive.NewObjectID().Hex()},
+        {"shuffled", true, primitive.NewObjectID().Hex()},
+        {"shuffled without participant", true, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            session := models.Session{ID: primitive.NewObjectID(), ShuffleQuestions: tt.shuffle, Questions: questions}
+            view := ParticipantSessionView(session, tt.participantID)
+
+            seen := map[primitive.ObjectID]bool{}
+            for _, question := range view.Questions {
+                seen[question.ID] = true
+            }
+            if len(seen) != len(questions) {
+                t.Fatalf("view has %d distinct questions, want %d", len(seen), len(questions))
+            }
+
+            if !tt.shuffle || tt.participantID == "" {
+                for i, question := range view.Questions {
+                    if question.ID != questions[i].ID {
+                        t.Errorf("question %d moved although the view is not shuffled", i)
+                    }
+                }
+            }
+        })
+    }
+}
@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"RealTimePoll/internal/models"
+)
+
+// parseCSV reads one question per row: the question text, then its options in the following
+// columns. An optional header row ("question", "type", "required", option columns) names the
+// columns; without it every column after the text is an option. The title is not part of a CSV.
+func parseCSV(data []byte) *document {
+	doc := &document{};
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))));
+	reader.FieldsPerRecord = -1;
+	reader.TrimLeadingSpace = true;
+
+	textColumn, typeColumn, requiredColumn := 0, -1, -1;
+	firstRow := true;
+	for {
+		record, err := reader.Read();
+		if err == io.EOF {
+			break;
+		}
+		if err != nil {
+			var parseError *csv.ParseError;
+			if errors.As(err, &parseError) {
+				doc.fail(parseError.Line, "%s", parseError.Err.Error());
+			} else {
+				doc.fail(0, "%s", err.Error());
+			}
+			return doc;
+		}
+		line, _ := reader.FieldPos(0);
+
+		if firstRow {
+			firstRow = false;
+			if header := strings.ToLower(strings.TrimSpace(record[0])); header == "question" || header == "text" {
+				for column, name := range record {
+					switch strings.ToLower(strings.TrimSpace(name)) {
+					case "type":
+						typeColumn = column;
+					case "required":
+						requiredColumn = column;
+					}
+				}
+				continue;
+			}
+		}
+
+		if isBlankRecord(record) {
+			continue;
+		}
+
+		question := models.Question{Text: strings.TrimSpace(record[textColumn])};
+		for column, value := range record {
+			value = strings.TrimSpace(value);
+			switch column {
+			case textColumn:
+			case typeColumn:
+				question.Type = strings.ToLower(value);
+			case requiredColumn:
+				if value == "" {
+					continue;
+				}
+				required, err := parseFlag(value);
+				if err != nil {
+					doc.fail(line, "required must be yes or no, got '%s'", value);
+				}
+				question.Required = required;
+			default:
+				if value != "" {
+					question.Options = append(question.Options, value);
+				}
+			}
+		}
+
+		if question.Text == "" {
+			doc.fail(line, "question text is missing");
+		}
+		doc.session.Questions = append(doc.session.Questions, question);
+		doc.questionLines = append(doc.questionLines, line);
+	}
+	return doc;
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false;
+		}
+	}
+	return true;
+}
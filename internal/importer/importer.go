@@ -0,0 +1,208 @@
+// Package importer reads session definitions written outside the app (JSON, YAML, CSV or Markdown)
+// and turns them into draft sessions, checked with the same rules as a poll created through the API.
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	handlerUtil "RealTimePoll/internal/handlers/utils"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FormatJSON = "json";
+	FormatYAML = "yaml";
+	FormatCSV = "csv";
+	FormatMarkdown = "markdown";
+)
+
+// MaxDocumentBytes bounds an imported definition.
+const MaxDocumentBytes = 1 << 20;
+
+// problem found at a line of the imported document, Line 0 when it concerns the whole document.
+type LineError struct {
+	Line int `json:"line"`
+	Message string `json:"message"`
+}
+
+// Error lists everything wrong with an imported document.
+type Error struct {
+	Errors []LineError
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, len(e.Errors));
+	for i, lineError := range e.Errors {
+		if lineError.Line > 0 {
+			messages[i] = fmt.Sprintf("line %d: %s", lineError.Line, lineError.Message);
+		} else {
+			messages[i] = lineError.Message;
+		}
+	}
+	return strings.Join(messages, "; ");
+}
+
+// parsed document: the session and the line each of its questions starts on.
+type document struct {
+	session models.Session
+	titleLine int
+	questionLines []int
+	errors []LineError
+}
+
+func (d *document) fail(line int, format string, args ...interface{}) {
+	d.errors = append(d.errors, LineError{Line: line, Message: fmt.Sprintf(format, args...)});
+}
+
+func (d *document) questionLine(index int) int {
+	if index >= 0 && index < len(d.questionLines) {
+		return d.questionLines[index];
+	}
+	return 0;
+}
+
+// DetectFormat guesses the format from a file name, then a content type. It returns "" when unsure.
+func DetectFormat(fileName, contentType string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return FormatJSON;
+	case ".yaml", ".yml":
+		return FormatYAML;
+	case ".csv":
+		return FormatCSV;
+	case ".md", ".markdown":
+		return FormatMarkdown;
+	}
+
+	switch mediaType, _, _ := strings.Cut(contentType, ";"); strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "application/json":
+		return FormatJSON;
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML;
+	case "text/csv":
+		return FormatCSV;
+	case "text/markdown", "text/x-markdown":
+		return FormatMarkdown;
+	}
+	return "";
+}
+
+// Parse reads a session definition and returns it as a draft session with a fresh id and join code,
+// ready for services.SavePollQuestions. title is used when the document has none (CSV never has).
+// Problems are reported together as an *Error with the line they were found on.
+func Parse(format string, data []byte, title string) (*models.Session, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, &Error{Errors: []LineError{{Message: "document is empty"}}};
+	}
+	if len(data) > MaxDocumentBytes {
+		return nil, &Error{Errors: []LineError{{Message: fmt.Sprintf("document is larger than %d bytes", MaxDocumentBytes)}}};
+	}
+
+	var doc *document;
+	switch format {
+	case FormatJSON:
+		doc = parseJSON(data);
+	case FormatYAML:
+		doc = parseYAML(data);
+	case FormatCSV:
+		doc = parseCSV(data);
+	case FormatMarkdown:
+		doc = parseMarkdown(data);
+	default:
+		return nil, fmt.Errorf("format must be '%s', '%s', '%s' or '%s'", FormatJSON, FormatYAML, FormatCSV, FormatMarkdown);
+	}
+	if len(doc.errors) > 0 {
+		return nil, &Error{Errors: doc.errors};
+	}
+
+	session := doc.session;
+	if strings.TrimSpace(session.Title) == "" {
+		session.Title = strings.TrimSpace(title);
+	}
+	if len(session.JoinCode) > 0 {
+		doc.fail(0, "joinCode is computed by the server and cannot be imported");
+		return nil, &Error{Errors: doc.errors};
+	}
+
+	handlerUtil.ApplySessionDefaults(&session);
+	validate(doc, session);
+	if len(doc.errors) > 0 {
+		return nil, &Error{Errors: doc.errors};
+	}
+
+	nowTime, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339));
+	session.ID = primitive.NewObjectID();
+	session.Status = utils.DRAFT;
+	session.JoinCode = utils.GenerateJoinCode();
+	session.CreatedAt = nowTime;
+	session.UpdatedAt = nowTime;
+	return &session, nil;
+}
+
+// validate runs the CreateNewPoll rules. Questions are checked one by one so that every broken
+// question is reported with its line, display conditions once all questions passed.
+func validate(doc *document, session models.Session) {
+	for i, question := range session.Questions {
+		question.Key = "";
+		question.ShowIf = nil;
+
+		single := session;
+		single.Questions = []models.Question{question};
+		err := handlerUtil.ValidateSessionRequest(single);
+		if err == nil {
+			continue;
+		}
+
+		var questionError *handlerUtil.QuestionValidationError;
+		if !errors.As(err, &questionError) {
+			// a session-wide problem, the same for every question.
+			doc.fail(doc.titleLine, "%s", err.Error());
+			return;
+		}
+		doc.fail(doc.questionLine(i), "question %d: %s", i+1, questionError.Message);
+	}
+	if len(doc.errors) > 0 {
+		return;
+	}
+
+	err := handlerUtil.ValidateSessionRequest(session);
+	if err == nil {
+		return;
+	}
+	var questionError *handlerUtil.QuestionValidationError;
+	if errors.As(err, &questionError) {
+		doc.fail(doc.questionLine(questionError.Index), "%s", err.Error());
+		return;
+	}
+	doc.fail(doc.titleLine, "%s", err.Error());
+}
+
+// parseFlag reads yes/no style values of the CSV and Markdown formats.
+func parseFlag(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "true", "1", "x":
+		return true, nil;
+	case "no", "n", "false", "0", "":
+		return false, nil;
+	}
+	return false, fmt.Errorf("not a yes/no value: %s", value);
+}
+
+// lineAt returns the 1-based line of a byte offset.
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data));
+	}
+	if offset < 0 {
+		offset = 0;
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1;
+}
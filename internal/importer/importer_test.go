@@ -0,0 +1,232 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"RealTimePoll/internal/utils"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		fileName string
+		contentType string
+		want string
+	}{
+		{"json extension", "poll.json", "", FormatJSON},
+		{"yaml extension", "poll.yaml", "", FormatYAML},
+		{"yml extension", "POLL.YML", "", FormatYAML},
+		{"csv extension", "poll.csv", "", FormatCSV},
+		{"md extension", "retro.md", "", FormatMarkdown},
+		{"markdown extension", "retro.markdown", "", FormatMarkdown},
+		{"extension wins over content type", "poll.csv", "application/json", FormatCSV},
+		{"json content type", "", "application/json; charset=utf-8", FormatJSON},
+		{"yaml content type", "upload", "text/x-yaml", FormatYAML},
+		{"csv content type", "", "Text/CSV", FormatCSV},
+		{"markdown content type", "", "text/markdown", FormatMarkdown},
+		{"unknown", "poll.txt", "text/plain", ""},
+		{"nothing", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.fileName, tt.contentType); got != tt.want {
+				t.Errorf("DetectFormat(%q, %q) = %q, want %q", tt.fileName, tt.contentType, got, tt.want);
+			}
+		})
+	}
+}
+
+type wantQuestion struct {
+	text string
+	qType string
+	required bool
+	options []string
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		format string
+		data string
+		title string
+		wantTitle string
+		want []wantQuestion
+	}{
+		{
+			name: "json",
+			format: FormatJSON,
+			data: `{"title": "Lunch", "questions": [{"text": "Where?", "options": ["Pizza", "Sushi"]}, {"text": "Which?", "type": "multiple", "required": true, "options": ["A", "B", "C"]}]}`,
+			wantTitle: "Lunch",
+			want: []wantQuestion{
+				{"Where?", utils.SINGLE, false, []string{"Pizza", "Sushi"}},
+				{"Which?", utils.MULTIPLE, true, []string{"A", "B", "C"}},
+			},
+		},
+		{
+			name: "yaml",
+			format: FormatYAML,
+			data: "title: Lunch\nquestions:\n  - text: Where?\n    options: [Pizza, Sushi]\n  - text: Which?\n    type: multiple\n    required: true\n    options:\n      - A\n      - B\n",
+			wantTitle: "Lunch",
+			want: []wantQuestion{
+				{"Where?", utils.SINGLE, false, []string{"Pizza", "Sushi"}},
+				{"Which?", utils.MULTIPLE, true, []string{"A", "B"}},
+			},
+		},
+		{
+			name: "csv without header takes the given title",
+			format: FormatCSV,
+			data: "Where?,Pizza,Sushi\n\nWhich?, A , B,,C\n",
+			title: " Lunch ",
+			wantTitle: "Lunch",
+			want: []wantQuestion{
+				{"Where?", utils.SINGLE, false, []string{"Pizza", "Sushi"}},
+				{"Which?", utils.SINGLE, false, []string{"A", "B", "C"}},
+			},
+		},
+		{
+			name: "csv with header and byte order mark",
+			format: FormatCSV,
+			data: "\xef\xbb\xbfQuestion,Type,Required,Option 1,Option 2\nWhere?,multiple,yes,Pizza,Sushi\nWhich?,,no,A,B\n",
+			want: []wantQuestion{
+				{"Where?", utils.MULTIPLE, true, []string{"Pizza", "Sushi"}},
+				{"Which?", utils.SINGLE, false, []string{"A", "B"}},
+			},
+		},
+		{
+			name: "markdown",
+			format: FormatMarkdown,
+			data: "# Sprint retro\nHow it went, in two questions.\n\n## How did the sprint go?\ntype: single\n- Great\n- Okay\n\n## What should we change?\nType: Multiple\nrequired: yes\n* Standups\n* Reviews\n",
+			title: "ignored",
+			wantTitle: "Sprint retro",
+			want: []wantQuestion{
+				{"How did the sprint go?", utils.SINGLE, false, []string{"Great", "Okay"}},
+				{"What should we change?", utils.MULTIPLE, true, []string{"Standups", "Reviews"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := Parse(tt.format, []byte(tt.data), tt.title);
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err);
+			}
+			if session.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", session.Title, tt.wantTitle);
+			}
+			if session.Status != utils.DRAFT || session.ID.IsZero() || session.JoinCode == "" {
+				t.Errorf("session not a fresh draft: status %q, id %s, join code %q", session.Status, session.ID.Hex(), session.JoinCode);
+			}
+			if session.Mode != utils.POLL_MODE {
+				t.Errorf("mode = %q, want default %q", session.Mode, utils.POLL_MODE);
+			}
+
+			if len(session.Questions) != len(tt.want) {
+				t.Fatalf("got %d questions, want %d", len(session.Questions), len(tt.want));
+			}
+			for i, want := range tt.want {
+				question := session.Questions[i];
+				got := wantQuestion{question.Text, question.Type, question.Required, question.Options};
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("question %d = %+v, want %+v", i+1, got, want);
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		format string
+		data string
+		wantLines []int
+		wantMessage string
+	}{
+		{"empty document", FormatJSON, " \n\t", []int{0}, "document is empty"},
+		{"json syntax error", FormatJSON, "{\n  \"title\": \"Lunch\",\n  \"questions\": [\n}", []int{4}, "invalid character"},
+		{"json wrong type", FormatJSON, "{\n  \"title\": 5,\n  \"questions\": []\n}", []int{2}, "expected string"},
+		{"json invalid question", FormatJSON, "{\n  \"title\": \"Lunch\",\n  \"questions\": [\n    {\"text\": \"Ok?\", \"options\": [\"Yes\", \"No\"]},\n    {\"text\": \"Which?\", \"type\": \"bogus\", \"options\": [\"A\", \"B\"]}\n  ]\n}", []int{5}, "question 2"},
+		{"json join code", FormatJSON, `{"joinCode": "abc123", "questions": [{"text": "Ok?", "options": ["Yes", "No"]}]}`, []int{0}, "joinCode"},
+		{"json no questions", FormatJSON, `{"title": "Lunch", "questions": []}`, []int{1}, "at least one question"},
+		{"yaml syntax error", FormatYAML, "title: Lunch\nquestions:\n\t- text: Ok?\n", []int{3}, "cannot start any token"},
+		{"yaml not a mapping", FormatYAML, "- one\n- two\n", []int{1}, "must be a mapping"},
+		{"yaml questions not a list", FormatYAML, "title: Lunch\nquestions: nope\n", []int{2}, "questions must be a list"},
+		{"yaml wrong question field type", FormatYAML, "title: Lunch\nquestions:\n  - text: Ok?\n    options: [Yes, No]\n  - text: [1, 2]\n", []int{5}, "question 2"},
+		{"csv unclosed quote", FormatCSV, "Where?,Pizza,Sushi\n\"Which?,A,B\n", []int{2}, ""},
+		{"csv missing question text", FormatCSV, "Where?,Pizza,Sushi\n,A,B\n", []int{2}, "question text is missing"},
+		{"csv bad required flag", FormatCSV, "question,required,a,b\nWhere?,maybe,Pizza,Sushi\n", []int{2}, "required must be yes or no"},
+		{"markdown option outside question", FormatMarkdown, "# Retro\n- stray\n## Ok?\n- Yes\n- No\n", []int{2}, "option outside of a question"},
+		{"markdown title twice", FormatMarkdown, "# Retro\n# Again\n## Ok?\n- Yes\n- No\n", []int{2}, "already set on line 1"},
+		{"markdown title after questions", FormatMarkdown, "## Ok?\n- Yes\n- No\n# Retro\n", []int{4}, "before the questions"},
+		{"markdown unexpected line", FormatMarkdown, "# Retro\n## Ok?\nsome words\n- Yes\n- No\n", []int{3}, "unexpected line"},
+		{"markdown empty question", FormatMarkdown, "# Retro\n## \n- Yes\n- No\n", []int{2}, "question text is missing"},
+		{"markdown empty option", FormatMarkdown, "# Retro\n## Ok?\n- Yes\n-\n- No\n", []int{4}, "option text is missing"},
+		{"markdown several errors", FormatMarkdown, "# Retro\n##\n- Yes\n- No\n## Ok?\nrequired: maybe\n- Yes\n- No\n", []int{2, 6}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.format, []byte(tt.data), "");
+			var importError *Error;
+			if !errors.As(err, &importError) {
+				t.Fatalf("Parse() error = %v, want an *Error", err);
+			}
+
+			lines := []int{};
+			for _, lineError := range importError.Errors {
+				lines = append(lines, lineError.Line);
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("error lines = %v, want %v (%v)", lines, tt.wantLines, err);
+			}
+			if !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("error = %q, want it to mention %q", err.Error(), tt.wantMessage);
+			}
+		})
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	_, err := Parse("txt", []byte("Where?"), "");
+	var importError *Error;
+	if err == nil || errors.As(err, &importError) {
+		t.Errorf("Parse() error = %v, want a plain format error", err);
+	}
+}
+
+func TestParseTooLarge(t *testing.T) {
+	data := "Where?," + strings.Repeat("a", MaxDocumentBytes);
+	if _, err := Parse(FormatCSV, []byte(data), ""); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Parse() error = %v, want the size limit", err);
+	}
+}
+
+func TestParseFlag(t *testing.T) {
+	tests := []struct {
+		value string
+		want bool
+		wantErr bool
+	}{
+		{"yes", true, false},
+		{" Y ", true, false},
+		{"TRUE", true, false},
+		{"1", true, false},
+		{"x", true, false},
+		{"no", false, false},
+		{"N", false, false},
+		{"false", false, false},
+		{"0", false, false},
+		{"", false, false},
+		{"maybe", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseFlag(tt.value);
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("parseFlag(%q) = (%v, %v), want (%v, error %v)", tt.value, got, err, tt.want, tt.wantErr);
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"RealTimePoll/internal/models"
+)
+
+// parseMarkdown reads the outline format:
+//
+//	# Sprint retro
+//	## How did the sprint go?
+//	type: single
+//	- Great
+//	- Okay
+//
+// "# " is the title, every "## " starts a question and "- " (or "* ") lines are its options.
+// "type:" and "required:" lines set those fields of the current question. Text between the
+// title and the first question is taken as a description and skipped.
+func parseMarkdown(data []byte) *document {
+	doc := &document{};
+	scanner := bufio.NewScanner(bytes.NewReader(data));
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxDocumentBytes);
+
+	var current *models.Question;
+	lineNumber := 0;
+	for scanner.Scan() {
+		lineNumber++;
+		line := strings.TrimSpace(scanner.Text());
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff");
+		}
+
+		switch {
+		case line == "":
+		case isMarker(line, "##"):
+			text := strings.TrimSpace(strings.TrimPrefix(line, "##"));
+			if text == "" {
+				doc.fail(lineNumber, "question text is missing");
+			}
+			doc.session.Questions = append(doc.session.Questions, models.Question{Text: text});
+			doc.questionLines = append(doc.questionLines, lineNumber);
+			current = &doc.session.Questions[len(doc.session.Questions)-1];
+		case isMarker(line, "#"):
+			if doc.titleLine != 0 {
+				doc.fail(lineNumber, "the title is already set on line %d", doc.titleLine);
+				continue;
+			}
+			if current != nil {
+				doc.fail(lineNumber, "the title must come before the questions");
+				continue;
+			}
+			doc.session.Title = strings.TrimSpace(strings.TrimPrefix(line, "#"));
+			doc.titleLine = lineNumber;
+		case isMarker(line, "-") || isMarker(line, "*"):
+			if current == nil {
+				doc.fail(lineNumber, "option outside of a question, start the question with '## '");
+				continue;
+			}
+			option := strings.TrimSpace(line[1:]);
+			if option == "" {
+				doc.fail(lineNumber, "option text is missing");
+				continue;
+			}
+			current.Options = append(current.Options, option);
+		case current == nil:
+			// description below the title.
+		default:
+			key, value, found := strings.Cut(line, ":");
+			key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value);
+			switch {
+			case found && key == "type":
+				current.Type = strings.ToLower(value);
+			case found && key == "required":
+				required, err := parseFlag(value);
+				if err != nil {
+					doc.fail(lineNumber, "required must be yes or no, got '%s'", value);
+				}
+				current.Required = required;
+			default:
+				doc.fail(lineNumber, "unexpected line in a question, options start with '- '");
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		doc.fail(lineNumber+1, "%s", err.Error());
+	}
+	return doc;
+}
+
+// isMarker tells whether a trimmed line starts with the marker followed by a space, or is only
+// the marker, so that an empty heading or option is reported instead of read as text.
+func isMarker(line, marker string) bool {
+	return line == marker || strings.HasPrefix(line, marker+" ");
+}
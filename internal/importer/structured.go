@@ -0,0 +1,165 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+
+	"RealTimePoll/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseJSON reads a session in the body format of POST /sessions.
+func parseJSON(data []byte) *document {
+	doc := &document{titleLine: 1};
+	if err := json.Unmarshal(data, &doc.session); err != nil {
+		var syntaxError *json.SyntaxError;
+		var typeError *json.UnmarshalTypeError;
+		switch {
+		case errors.As(err, &syntaxError):
+			doc.fail(lineAt(data, syntaxError.Offset), "%s", syntaxError.Error());
+		case errors.As(err, &typeError):
+			doc.fail(lineAt(data, typeError.Offset), "%s: expected %s, got %s", typeError.Field, typeError.Type.String(), typeError.Value);
+		default:
+			doc.fail(0, "%s", err.Error());
+		}
+		return doc;
+	}
+
+	doc.titleLine, doc.questionLines = jsonLines(data);
+	return doc;
+}
+
+// jsonLines walks the top-level object for the line of "title" and of each element of "questions".
+// The document already decoded, so a failing walk only costs the line numbers.
+func jsonLines(data []byte) (int, []int) {
+	titleLine, questionLines := 1, []int{};
+	decoder := json.NewDecoder(bytes.NewReader(data));
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return titleLine, questionLines;
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token();
+		if err != nil {
+			return titleLine, questionLines;
+		}
+		key, _ := token.(string);
+		keyLine := lineAt(data, decoder.InputOffset());
+
+		if key != "questions" {
+			if key == "title" {
+				titleLine = keyLine;
+			}
+			var skipped json.RawMessage;
+			if err := decoder.Decode(&skipped); err != nil {
+				return titleLine, questionLines;
+			}
+			continue;
+		}
+
+		if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+			return titleLine, questionLines;
+		}
+		for decoder.More() {
+			questionLines = append(questionLines, lineAt(data, nextValueOffset(data, decoder.InputOffset())));
+			var skipped json.RawMessage;
+			if err := decoder.Decode(&skipped); err != nil {
+				return titleLine, questionLines;
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return titleLine, questionLines;
+		}
+	}
+	return titleLine, questionLines;
+}
+
+// nextValueOffset skips the separators between the decoder's position and the next value.
+func nextValueOffset(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++;
+		default:
+			return offset;
+		}
+	}
+	return offset;
+}
+
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`);
+
+// parseYAML reads the same structure as parseJSON, with the JSON field names as keys.
+// Each top-level key and question is decoded on its own so errors point at its line.
+func parseYAML(data []byte) *document {
+	doc := &document{titleLine: 1};
+
+	var root yaml.Node;
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		line := 0;
+		if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1]);
+		}
+		doc.fail(line, "%s", err.Error());
+		return doc;
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		doc.fail(1, "a session must be a mapping with title and questions");
+		return doc;
+	}
+
+	mapping := root.Content[0];
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keyNode, valueNode := mapping.Content[i], mapping.Content[i+1];
+
+		switch keyNode.Value {
+		case "title":
+			doc.titleLine = keyNode.Line;
+		case "questions":
+			if valueNode.Kind != yaml.SequenceNode {
+				doc.fail(valueNode.Line, "questions must be a list");
+				continue;
+			}
+			for _, questionNode := range valueNode.Content {
+				var question models.Question;
+				if err := decodeYAMLNode(questionNode, &question); err != nil {
+					doc.fail(questionNode.Line, "question %d: %s", len(doc.questionLines)+1, err.Error());
+				}
+				doc.session.Questions = append(doc.session.Questions, question);
+				doc.questionLines = append(doc.questionLines, questionNode.Line);
+			}
+			continue;
+		}
+
+		field := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{keyNode, valueNode}};
+		if err := decodeYAMLNode(field, &doc.session); err != nil {
+			doc.fail(keyNode.Line, "%s", err.Error());
+		}
+	}
+	return doc;
+}
+
+// decodeYAMLNode decodes a node through JSON, so the models' json field names apply.
+func decodeYAMLNode(node *yaml.Node, target interface{}) error {
+	var value interface{};
+	if err := node.Decode(&value); err != nil {
+		return err;
+	}
+	encoded, err := json.Marshal(value);
+	if err != nil {
+		return err;
+	}
+
+	if err := json.Unmarshal(encoded, target); err != nil {
+		var typeError *json.UnmarshalTypeError;
+		if errors.As(err, &typeError) {
+			return errors.New(typeError.Field + ": expected " + typeError.Type.String() + ", got " + typeError.Value);
+		}
+		return err;
+	}
+	return nil;
+}
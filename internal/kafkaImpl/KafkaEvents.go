@@ -14,10 +14,16 @@ type VoteSubmittedEvent struct {
     QuestionID    string    `json:"questionId"`
     ParticipantID string    `json:"participantId"`
     SelectedOptions []int   `json:"selectedOptions"`
+    OtherText     string    `json:"otherText,omitempty"`
+    Availability  []string  `json:"availability,omitempty"`
+    Allocation    []int     `json:"allocation,omitempty"`
+    RowAnswers    []int     `json:"rowAnswers,omitempty"`
+    SealedBallot  string    `json:"sealedBallot,omitempty"` // secret ballots: the answer fields above, encrypted
     Timestamp     time.Time `json:"timestamp"`
     // Add any metadata needed for processing
     IPAddress     string    `json:"ipAddress,omitempty"`
     UserAgent     string    `json:"userAgent,omitempty"`
+    FromWaitlist  bool      `json:"fromWaitlist,omitempty"` // replayed from a full option's waitlist
 }
 
 type VoteProcessedEvent struct {
@@ -37,5 +43,75 @@ type ResultsUpdatedEvent struct {
     SessionID string         `json:"sessionId"`
     QuestionID string        `json:"questionId"`
     Results   models.QuestionResult `json:"results"`
+    PublicResults *models.QuestionResult `json:"publicResults,omitempty"` // privacy sessions: what participants see
+    OrganizerID string       `json:"organizerId,omitempty"` // the session's organizer, who always sees exact results
+    Visibility string        `json:"visibility,omitempty"` // results visibility policy of the question
+    RetractedBy string       `json:"retractedBy,omitempty"` // participant whose vote was just retracted
     Timestamp time.Time      `json:"timestamp"`
-}
\ No newline at end of file
+}
+
+type LeaderboardUpdatedEvent struct {
+    EventID     string             `json:"eventId"`
+    Type        string             `json:"type"` // "quiz.leaderboard"
+    SessionID   string             `json:"sessionId"`
+    Leaderboard models.Leaderboard `json:"leaderboard"`
+    Timestamp   time.Time          `json:"timestamp"`
+}
+
+type QnAUpdatedEvent struct {
+    EventID   string                    `json:"eventId"`
+    Type      string                    `json:"type"` // "qna.updated"
+    SessionID string                    `json:"sessionId"`
+    Questions []models.AudienceQuestion `json:"questions"` // ranked, including the moderation queue
+    OrganizerID string                  `json:"organizerId,omitempty"` // the session's organizer, the only one who sees the queue
+    Timestamp time.Time                 `json:"timestamp"`
+}
+
+// one event for a whole survey submission, processed all-or-nothing.
+type SurveySubmittedEvent struct {
+    EventID       string              `json:"eventId"`
+    Type          string              `json:"type"` // "survey.submitted"
+    SubmissionID  string              `json:"submissionId"`
+    SessionID     string              `json:"sessionId"`
+    ParticipantID string              `json:"participantId"`
+    Answers       []SurveyAnswerEvent `json:"answers"`
+    Timestamp     time.Time           `json:"timestamp"`
+    IPAddress     string              `json:"ipAddress,omitempty"`
+    UserAgent     string              `json:"userAgent,omitempty"`
+}
+
+type SurveyAnswerEvent struct {
+    VoteID          string `json:"voteId"`
+    QuestionID      string `json:"questionId"`
+    SelectedOptions []int  `json:"selectedOptions"`
+    OtherText       string `json:"otherText,omitempty"`
+    Availability    []string `json:"availability,omitempty"`
+    Allocation      []int    `json:"allocation,omitempty"`
+    RowAnswers      []int    `json:"rowAnswers,omitempty"`
+    SealedBallot    string   `json:"sealedBallot,omitempty"` // secret ballots: the answer fields above, encrypted
+}
+
+// emitted when a session or a single question stops accepting votes, with the final results.
+type ClosureEvent struct {
+    EventID    string                  `json:"eventId"`
+    Type       string                  `json:"type"` // "sessions.closed"
+    SessionID  string                  `json:"sessionId"`
+    QuestionID string                  `json:"questionId,omitempty"` // empty when the whole session closed
+    Reason     string                  `json:"reason"`
+    Results    []models.QuestionResult `json:"results"`
+    Audits     []models.TallyAudit     `json:"audits,omitempty"` // merkle roots, set when the whole session closed
+    PublicResults []models.QuestionResult `json:"publicResults,omitempty"` // privacy sessions: what participants see
+    HiddenQuestionIDs []string         `json:"hiddenQuestionIds,omitempty"` // organizer_only results, left out for participants
+    OrganizerID string                 `json:"organizerId,omitempty"`
+    Timestamp  time.Time               `json:"timestamp"`
+}
+
+// signed vote receipt on its way to the participant who cast the vote.
+type ReceiptIssuedEvent struct {
+    EventID       string             `json:"eventId"`
+    Type          string             `json:"type"` // "votes.receipts"
+    SessionID     string             `json:"sessionId"`
+    ParticipantID string             `json:"participantId"`
+    Receipt       models.VoteReceipt `json:"receipt"`
+    Timestamp     time.Time          `json:"timestamp"`
+}
@@ -5,6 +5,8 @@ import (
 	"RealTimePoll/internal/models"
 	"RealTimePoll/internal/utils"
 	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/storage"
 
 	"context"
 	"encoding/json"
@@ -18,6 +20,14 @@ import (
 
 // This method processes vote and sends to kafka leading to websockets eventually to all clients to display results realtime.
 func processVote(voteEvent VoteSubmittedEvent) error {
+	if err := placeVote(voteEvent); err != errWaitlisted {
+		return err;
+	}
+	return nil;
+}
+
+// placeVote stores a vote, or queues it and returns errWaitlisted when an option it takes is full.
+func placeVote(voteEvent VoteSubmittedEvent) error {
 	// ctx := context.Background();
 
 	 sessionID, err := primitive.ObjectIDFromHex(voteEvent.SessionID)
@@ -30,34 +40,96 @@ func processVote(voteEvent VoteSubmittedEvent) error {
         return fmt.Errorf("invalid question ID: %v", err)
     }
 
-	_, err = repository.GetSessionByID(sessionID);
+	participantObjID, err := primitive.ObjectIDFromHex(voteEvent.ParticipantID);
+	if err != nil {
+		return fmt.Errorf("invalid participant ID: %v", err);
+	}
+
+	session, err := repository.GetSessionByID(sessionID);
 	if err != nil {
 		return fmt.Errorf("session not found: %v", err);
 	}
 
+	// votes still queued when the session closed would change results already published.
+	if session.Status != utils.ACTIVE {
+		return fmt.Errorf("session is not active");
+	}
+
+	question, found := session.FindQuestion(questionID);
+	if !found {
+		return fmt.Errorf("question not found");
+	}
+
+	if question.ClosedAt != nil {
+		return fmt.Errorf("question is closed");
+	}
+
+	if session.IsQuiz() && question.OpenedAt == nil {
+		return fmt.Errorf("question is not open");
+	}
+
+	if err := checkQuestionsShown(session, []models.Question{question}, voteEvent.ParticipantID, nil); err != nil {
+		return err;
+	}
+
+	weight, err := participantWeight(session, voteEvent.ParticipantID);
+	if err != nil {
+		return err;
+	}
+
 	// deduplication check
-	if err := checkDuplicateVote(sessionID, questionID, voteEvent.ParticipantID); err != nil {
+	voterKey := dedupID(session, questionID, voteEvent.ParticipantID);
+	if err := checkDuplicateVote(sessionID, questionID, voterKey); err != nil {
+		if question.AllowsVoteChange() && !voteEvent.FromWaitlist {
+			return replaceVote(session, question, voteEvent);
+		}
 		return fmt.Errorf("duplicate vote: %v", err);
 	}
 
-	nowTime := time.Now();
-	voteObjectID,_ := primitive.ObjectIDFromHex(voteEvent.VoteID);
-	participantObjID, _ :=  primitive.ObjectIDFromHex(voteEvent.ParticipantID);
-
-	vote := models.Vote{
-		ID : voteObjectID,
-		SessionID: sessionID,
-		QuestionID: questionID,
-		ParticipantID: participantObjID,
-		SelectedOptions: voteEvent.SelectedOptions,
-		CreatedAt: nowTime,
-		Processed: true,
-		ProcessedAt: nowTime,
+	if full, err := reserveSeats(session, question, voteEvent.SelectedOptions, nil); err != nil {
+		releaseVoteLock(sessionID, questionID, voterKey);
+		if err == errOptionFull && question.Waitlist {
+			if err := joinWaitlist(session, question, full, voteEvent); err != nil {
+				return err;
+			}
+			return errWaitlisted;
+		}
+		return err;
 	}
 
-	if err := repository.SaveVoteToMongo(vote); err != nil {
+	vote := buildVote(session, question, voteEvent.VoteID, participantObjID, voteEvent.SelectedOptions, voteEvent.OtherText, voteEvent.Timestamp);
+	vote.Weight = weight;
+	vote.Availability = voteEvent.Availability;
+	vote.Allocation = voteEvent.Allocation;
+	vote.RowAnswers = voteEvent.RowAnswers;
+	voteReceipt := sealBallot(&vote, voteEvent.VoteID);
+
+	if session.SecretBallot {
+		ballot, participation := toSecretBallot(vote, voteEvent.ParticipantID);
+		err = repository.SaveSecretBallots([]models.Vote{ballot}, []models.Participation{participation});
+		vote = ballot;
+	} else {
+		err = repository.SaveVoteToMongo(vote);
+	}
+	if err != nil {
+		releaseVoteLock(sessionID, questionID, voterKey);
+		services.ReleaseSeats(sessionID, questionID, question.OptionCapacities, voteEvent.SelectedOptions);
 		return fmt.Errorf("failed to save vote: %v", err);
 	}
+	deliverReceipt(session, voteReceipt, voteEvent.ParticipantID);
+	recordQuestionViews(session, []models.Question{question}, voteEvent.ParticipantID);
+
+	if session.HasRoster() {
+		if err := services.MarkRosterVoted(sessionID, voteEvent.ParticipantID); err != nil {
+			log.Printf("Warning: Failed to update roster turnout: %v", err)
+		}
+	}
+
+	if session.IsQuiz() {
+		if err := services.AddQuizScore(sessionID, voteEvent.ParticipantID, vote.Score); err != nil {
+			log.Printf("Warning: Failed to update quiz leaderboard: %v", err)
+		}
+	}
 
 	// need to implement.
 	if err := UpdateRealTimeResults(vote); err != nil {
@@ -65,19 +137,56 @@ func processVote(voteEvent VoteSubmittedEvent) error {
         // Don't fail the entire process if results update fails
     }
 
+	evaluateClosingRules(sessionID, questionID);
+
 	log.Printf("Successfully processed vote: %s", voteEvent.VoteID)
     return nil
 }
 
+// buildVote creates the vote document for one answer, scoring it when the session is a quiz.
+func buildVote(session *models.Session, question models.Question, voteID string, participantObjID primitive.ObjectID, selected []int, otherText string, submittedAt time.Time) models.Vote {
+	nowTime := time.Now();
+	voteObjectID,_ := primitive.ObjectIDFromHex(voteID);
+
+	vote := models.Vote{
+		ID : voteObjectID,
+		SessionID: session.ID,
+		QuestionID: question.ID,
+		ParticipantID: participantObjID,
+		SelectedOptions: selected,
+		OtherText: otherText,
+		OtherTextNormalized: utils.NormalizeFreeText(otherText),
+		CreatedAt: nowTime,
+		Processed: true,
+		ProcessedAt: nowTime,
+	}
+
+	if session.IsQuiz() && len(question.CorrectOptions) > 0 {
+		vote.Correct, vote.Score = scoreAnswer(question, selected, submittedAt);
+	}
+	return vote;
+}
+
+func voteLockKey(sessionID, questionID primitive.ObjectID, participantID string) string {
+	return fmt.Sprintf("vote_lock:%s:%s:%s", sessionID.Hex(), questionID.Hex(), participantID);
+}
+
+// releaseVoteLock frees the deduplication lock when a vote could not be stored.
+func releaseVoteLock(sessionID, questionID primitive.ObjectID, participantID string) {
+	redisDb := database.GetRedisInstance();
+	if err := redisDb.GetClient().Del(context.Background(), voteLockKey(sessionID, questionID, participantID)).Err(); err != nil {
+		log.Printf("Warning: Failed to release vote lock: %v", err)
+	}
+}
+
 // This function checks and prevents duplicate voting.
 func checkDuplicateVote(sessionID, questionID primitive.ObjectID, participantID string) error {
 	redisDb := database.GetRedisInstance();
 	ctx := context.Background();
 
-	voteLockKey := fmt.Sprintf("vote_lock:%s:%s:%s", 
-        sessionID.Hex(), questionID.Hex(), participantID)
+	lockKey := voteLockKey(sessionID, questionID, participantID);
 
-	acquired, err := redisDb.GetClient().SetNX(ctx, voteLockKey, "1", 24*time.Hour).Result();
+	acquired, err := redisDb.GetClient().SetNX(ctx, lockKey, "1", 24*time.Hour).Result();
 	 if err != nil {
         return fmt.Errorf("redis error: %v", err)
     }
@@ -104,26 +213,15 @@ func UpdateRealTimeResults(vote models.Vote) error {
         return fmt.Errorf("failed to get session: %v", err)
     }
 
-	var question models.Question;
-
-	questionFound := false;
-	for _, q := range session.Questions {
-		if q.ID == vote.QuestionID {
-			question = q;
-			questionFound = true;
-			break;
-		}
-	}
-
-
+	question, questionFound := session.FindQuestion(vote.QuestionID);
 	if (! questionFound) {
 		 return fmt.Errorf("question not found in session");
 	}
 
 	// calculate the updated results for this question.
-	results, err := calculateQuestionResults(vote.QuestionID, vote.SessionID, question);
+	results, err := buildQuestionResults(session, question);
 	if err != nil {
-        return fmt.Errorf("failed to calculate results: %v", err)
+        return err
     }
 
 	// cache results in redis for faster access.
@@ -139,8 +237,17 @@ func UpdateRealTimeResults(vote models.Vote) error {
 		SessionID: vote.SessionID.Hex(),
 		QuestionID: vote.QuestionID.Hex(),
 		Results : results,
+		OrganizerID: session.OrganizerId.Hex(),
+		Visibility: question.ResultsVisibility,
 		Timestamp: time.Now(),
 	}
+	if session.HasPrivacy() {
+		public := publicResults(session, question, results);
+		resultsEvent.PublicResults = &public;
+	}
+	if vote.Retracted {
+		resultsEvent.RetractedBy = vote.ParticipantID.Hex();
+	}
 
 
 	// Send to kafka as a result it will lead to real-time broadcast.
@@ -155,6 +262,49 @@ func UpdateRealTimeResults(vote models.Vote) error {
 }
 
 
+// buildQuestionResults computes the full results of a question: headcount, weights and quorum.
+func buildQuestionResults(session *models.Session, question models.Question) (models.QuestionResult, error) {
+	results, err := calculateQuestionResults(question.ID, session.ID, question);
+	if err != nil {
+        return models.QuestionResult{}, fmt.Errorf("failed to calculate results: %v", err)
+    }
+
+	if session.HasBranching() {
+		if results.SeenCount, err = services.CountQuestionViews(session.ID, question.ID); err != nil {
+			return models.QuestionResult{}, err
+		}
+	}
+
+	if err := addCapacityResults(session, question, &results); err != nil {
+		return models.QuestionResult{}, err
+	}
+
+	if err := addScheduleResults(session, question, &results); err != nil {
+		return models.QuestionResult{}, err
+	}
+
+	// secret ballots carry no participant, voters are counted from participation records.
+	if session.SecretBallot {
+		if results.VotersCount, err = repository.CountParticipations(session.ID, question.ID); err != nil {
+			return models.QuestionResult{}, err
+		}
+	}
+
+	if err := addAllocationResults(session, question, &results); err != nil {
+		return models.QuestionResult{}, err
+	}
+
+	if err := addMatrixResults(session, question, &results); err != nil {
+		return models.QuestionResult{}, err
+	}
+
+	if err := applyWeightedResults(session, question, &results); err != nil {
+		return models.QuestionResult{}, fmt.Errorf("failed to calculate weighted results: %v", err)
+	}
+	return results, nil
+}
+
+
 func calculateQuestionResults(questionID, sessionID primitive.ObjectID, question models.Question) (models.QuestionResult, error) {
 	// get vote count for each option
 	voteCounts , totalVotes, err := getVoteCountsForQuestion(questionID, sessionID)
@@ -180,6 +330,13 @@ func calculateQuestionResults(questionID, sessionID primitive.ObjectID, question
         }
 	}
 
+	for i, optionImage := range storage.ResolveOptionImages(storage.Default(), question.OptionImages) {
+		if i < len(options) {
+			options[i].ImageURL = optionImage.URL;
+			options[i].ThumbnailURL = optionImage.ThumbnailURL;
+		}
+	}
+
 	// Get unique voters count for this question.
 	votersCount, err := getUniqueVotersCount(questionID, sessionID);
 
@@ -258,6 +415,7 @@ func getUniqueVotersCount(questionID, sessionID primitive.ObjectID) (int, error)
     distinctVoters, err := votesCollection.Distinct(ctx, "participant_id", bson.M{
         "session_id":  sessionID,
         "question_id": questionID,
+        "retracted":   bson.M{"$ne": true},
     })
 
     if err != nil {
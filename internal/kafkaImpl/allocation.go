@@ -0,0 +1,36 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+)
+
+// addAllocationResults adds the points of every option of an allocation question. Count already holds
+// the supporters (participants giving the option any points), the mean is over every participant.
+func addAllocationResults(session *models.Session, question models.Question, results *models.QuestionResult) error {
+	if question.Type != utils.ALLOCATION {
+		return nil;
+	}
+
+	points, err := services.SumAllocations(session.ID, question.ID);
+	if err != nil {
+		return err;
+	}
+
+	results.TotalPoints = 0;
+	for i := range results.Options {
+		results.Options[i].Points = points[i];
+		results.TotalPoints += points[i];
+	}
+
+	for i := range results.Options {
+		if results.VotersCount > 0 {
+			results.Options[i].MeanPoints = float64(results.Options[i].Points) / float64(results.VotersCount);
+		}
+		if results.TotalPoints > 0 {
+			results.Options[i].PointsPercentage = float64(results.Options[i].Points) / float64(results.TotalPoints) * 100;
+		}
+	}
+	return nil;
+}
@@ -0,0 +1,46 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+
+	"fmt"
+	"log"
+)
+
+// checkQuestionsShown rejects answers to questions the participant's display conditions hide.
+// Answers submitted together (a survey) count as given.
+func checkQuestionsShown(session *models.Session, questions []models.Question, participantID string, submitted models.ParticipantAnswers) error {
+	if !session.HasBranching() {
+		return nil;
+	}
+
+	answers, err := services.GetParticipantAnswers(session.ID, participantID);
+	if err != nil {
+		return err;
+	}
+	for questionID, selected := range submitted {
+		answers[questionID] = selected;
+	}
+
+	for _, question := range questions {
+		if !session.QuestionVisible(question, answers) {
+			return fmt.Errorf("question not shown to participant");
+		}
+	}
+	return nil;
+}
+
+// recordQuestionViews counts answered questions as seen, for participants who vote without
+// asking for their next question first.
+func recordQuestionViews(session *models.Session, questions []models.Question, participantID string) {
+	if !session.HasBranching() {
+		return;
+	}
+
+	for _, question := range questions {
+		if err := services.RecordQuestionView(session.ID, question.ID, participantID); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+var errOptionFull = fmt.Errorf("option is full");
+var errWaitlisted = fmt.Errorf("vote waitlisted");
+
+// reserveSeats takes a seat on the limited options of a selection, skipping the ones already held.
+// It fails with errOptionFull and takes nothing when any of them is full.
+func reserveSeats(session *models.Session, question models.Question, selected []int, held []int) (int, error) {
+	if !question.HasCapacity() {
+		return -1, nil;
+	}
+
+	full, err := services.ReserveSeats(session.ID, question.ID, question.OptionCapacities, withoutOptions(selected, held));
+	if err != nil {
+		return -1, err;
+	}
+	if full >= 0 {
+		return full, errOptionFull;
+	}
+	return -1, nil;
+}
+
+// releaseSeats frees the seats of options no longer held and hands them to the waitlist.
+func releaseSeats(session *models.Session, question models.Question, released []int) {
+	if !question.HasCapacity() || len(released) == 0 {
+		return;
+	}
+
+	services.ReleaseSeats(session.ID, question.ID, question.OptionCapacities, released);
+	for _, option := range released {
+		if option < len(question.OptionCapacities) && question.OptionCapacities[option] > 0 {
+			promoteWaitlist(session, question, option);
+		}
+	}
+}
+
+// joinWaitlist queues a vote on the full option, it is processed again once a seat frees up.
+func joinWaitlist(session *models.Session, question models.Question, option int, voteEvent VoteSubmittedEvent) error {
+	voteEvent.FromWaitlist = true;
+	entry, err := json.Marshal(voteEvent);
+	if err != nil {
+		return fmt.Errorf("failed to marshal waitlist entry: %v", err);
+	}
+
+	position, err := services.AddToWaitlist(session.ID, question.ID, option, entry);
+	if err != nil {
+		return err;
+	}
+
+	log.Printf("Vote %s waitlisted on option %d of question %s at position %d", voteEvent.VoteID, option, question.ID.Hex(), position)
+	if err := UpdateRealTimeResults(models.Vote{SessionID: session.ID, QuestionID: question.ID}); err != nil {
+		log.Printf("Warning: Failed to update real-time results: %v", err)
+	}
+	return nil;
+}
+
+// promoteWaitlist gives a freed seat to the first waitlisted vote that still applies. Entries that
+// no longer do (participant voted meanwhile, question closed) are dropped, and an entry that is
+// waitlisted again on another full option does not take the seat.
+func promoteWaitlist(session *models.Session, question models.Question, option int) {
+	for {
+		entry, err := services.PopWaitlist(session.ID, question.ID, option);
+		if err != nil {
+			log.Printf("Warning: Failed to read waitlist: %v", err)
+			return;
+		}
+		if entry == nil {
+			return;
+		}
+
+		var voteEvent VoteSubmittedEvent;
+		if err := json.Unmarshal(entry, &voteEvent); err != nil {
+			log.Printf("Warning: Dropping unreadable waitlist entry: %v", err)
+			continue;
+		}
+
+		err = placeVote(voteEvent);
+		if err == errWaitlisted {
+			continue;
+		}
+		if err != nil {
+			log.Printf("Waitlisted vote %s dropped: %v", voteEvent.VoteID, err)
+			continue;
+		}
+		log.Printf("Waitlisted vote %s promoted on option %d", voteEvent.VoteID, option)
+		return;
+	}
+}
+
+// addCapacityResults adds the seats left and the waitlist length of every limited option.
+func addCapacityResults(session *models.Session, question models.Question, results *models.QuestionResult) error {
+	if !question.HasCapacity() {
+		return nil;
+	}
+
+	for i := range results.Options {
+		if i >= len(question.OptionCapacities) || question.OptionCapacities[i] == 0 {
+			continue;
+		}
+
+		taken, err := services.GetTakenSeats(session.ID, question.ID, i);
+		if err != nil {
+			return err;
+		}
+		remaining := question.OptionCapacities[i] - taken;
+		if remaining < 0 {
+			remaining = 0;
+		}
+
+		results.Options[i].Capacity = question.OptionCapacities[i];
+		results.Options[i].Remaining = &remaining;
+		if question.Waitlist {
+			if results.Options[i].Waitlisted, err = services.GetWaitlistLength(session.ID, question.ID, i); err != nil {
+				return err;
+			}
+		}
+	}
+	return nil;
+}
+
+func withoutOptions(options []int, excluded []int) []int {
+	result := []int{};
+	for _, option := range options {
+		if !containsOption(excluded, option) {
+			result = append(result, option);
+		}
+	}
+	return result;
+}
+
+func containsOption(options []int, option int) bool {
+	for _, o := range options {
+		if o == option {
+			return true;
+		}
+	}
+	return false;
+}
@@ -0,0 +1,294 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const closingSchedulerInterval = 30 * time.Second
+
+// evaluateClosingRules runs after a vote was processed and closes the question and/or
+// the session when one of their closing rules is met.
+func evaluateClosingRules(sessionID, questionID primitive.ObjectID) {
+	session, err := repository.GetSessionByID(sessionID);
+	if err != nil || session.Status != utils.ACTIVE {
+		return;
+	}
+
+	if question, found := session.FindQuestion(questionID); found {
+		evaluateQuestionRules(session, question);
+	}
+	evaluateSessionRules(session);
+}
+
+func evaluateQuestionRules(session *models.Session, question models.Question) {
+	rules := question.ClosingRules;
+	if rules == nil || question.ClosedAt != nil {
+		return;
+	}
+
+	reason := "";
+	if rules.Deadline != nil && !time.Now().Before(*rules.Deadline) {
+		reason = "deadline";
+	} else {
+		results, err := buildQuestionResults(session, question);
+		if err != nil {
+			log.Printf("Failed to evaluate closing rules of question %s: %v", question.ID.Hex(), err)
+			return;
+		}
+
+		switch {
+		case rules.OnQuorum && results.Quorum != nil && results.Quorum.Reached:
+			reason = "quorum";
+		case rules.MaxVotes > 0 && results.VotersCount >= rules.MaxVotes:
+			reason = "max_votes";
+		case rules.AllRosterVoted:
+			eligible, _, err := services.GetRosterTotals(session.ID);
+			if err == nil && eligible > 0 && results.VotersCount >= eligible {
+				reason = "all_roster_voted";
+			}
+		}
+	}
+
+	if reason == "" {
+		return;
+	}
+
+	if err := CloseQuestion(session, question, reason); err != nil && err != ErrClosingInProgress {
+		log.Printf("Failed to close question %s: %v", question.ID.Hex(), err)
+	}
+}
+
+func evaluateSessionRules(session *models.Session) {
+	rules := session.ClosingRules;
+	if rules == nil {
+		return;
+	}
+
+	reason := "";
+	switch {
+	case rules.Deadline != nil && !time.Now().Before(*rules.Deadline):
+		reason = "deadline";
+
+	case rules.MaxVotes > 0:
+		if count, err := repository.CountSessionVotes(session.ID); err == nil && count >= int64(rules.MaxVotes) {
+			reason = "max_votes";
+		}
+	}
+
+	if reason == "" && (rules.OnQuorum || rules.AllRosterVoted) {
+		eligibleCount, eligibleWeight, err := services.GetRosterTotals(session.ID);
+		if err != nil {
+			log.Printf("Failed to evaluate closing rules of session %s: %v", session.ID.Hex(), err)
+			return;
+		}
+		votedCount, votedWeight, err := services.GetRosterVotedTotals(session.ID);
+		if err != nil {
+			log.Printf("Failed to evaluate closing rules of session %s: %v", session.ID.Hex(), err)
+			return;
+		}
+
+		eligible, voted := float64(eligibleCount), float64(votedCount);
+		if session.Weighted {
+			eligible, voted = eligibleWeight, votedWeight;
+		}
+
+		if rules.OnQuorum && eligible > 0 && voted / eligible * 100 >= session.QuorumPercent {
+			reason = "quorum";
+		} else if rules.AllRosterVoted && eligibleCount > 0 && votedCount >= eligibleCount {
+			reason = "all_roster_voted";
+		}
+	}
+
+	if reason == "" {
+		return;
+	}
+
+	if err := CloseSession(session.ID, reason); err != nil && err != ErrClosingInProgress {
+		log.Printf("Failed to close session %s: %v", session.ID.Hex(), err)
+	}
+}
+
+// CloseQuestion stops a question from accepting votes and broadcasts its final results.
+func CloseQuestion(session *models.Session, question models.Question, reason string) error {
+	release, err := acquireClosingLock(session.ID, question.ID);
+	if err != nil {
+		return err;
+	}
+	defer release();
+
+	if err := services.SetQuestionClosed(session.ID, question.ID); err != nil {
+		return err;
+	}
+
+	results, err := buildQuestionResults(session, question);
+	if err != nil {
+		return err;
+	}
+
+	if err := publishClosure(session, question.ID, reason, []models.QuestionResult{results}, nil); err != nil {
+		return err;
+	}
+
+	if session.IsQuiz() {
+		if err := PublishLeaderboard(session.ID, question.ID); err != nil {
+			log.Printf("Failed to publish leaderboard: %v", err)
+		}
+	}
+
+	log.Printf("Question %s of session %s closed: %s", question.ID.Hex(), session.ID.Hex(), reason)
+	return nil;
+}
+
+// CloseSession moves a session to closed through the same status transition as the status
+// endpoint and broadcasts the final results of every question.
+func CloseSession(sessionID primitive.ObjectID, reason string) error {
+	release, err := acquireClosingLock(sessionID, primitive.NilObjectID);
+	if err != nil {
+		return err;
+	}
+	defer release();
+
+	if err = services.UpdateSessionStatus(sessionID, utils.CLOSED); err != nil {
+		return err;
+	}
+
+	session, err := repository.GetSessionByID(sessionID);
+	if err != nil {
+		return fmt.Errorf("session not found: %v", err);
+	}
+
+	results := []models.QuestionResult{};
+	for _, question := range session.Questions {
+		questionResults, err := buildQuestionResults(session, question);
+		if err != nil {
+			log.Printf("Failed to build final results of question %s: %v", question.ID.Hex(), err)
+			continue;
+		}
+		results = append(results, questionResults);
+	}
+
+	audits, err := publishTallyAudits(session);
+	if err != nil {
+		log.Printf("Failed to publish tally audit of session %s: %v", sessionID.Hex(), err)
+	}
+
+	if err := publishClosure(session, primitive.NilObjectID, reason, results, audits); err != nil {
+		return err;
+	}
+
+	if session.IsQuiz() {
+		if err := PublishLeaderboard(sessionID, primitive.NilObjectID); err != nil {
+			log.Printf("Failed to publish leaderboard: %v", err)
+		}
+	}
+
+	log.Printf("Session %s closed: %s", sessionID.Hex(), reason)
+	return nil;
+}
+
+func publishClosure(session *models.Session, questionID primitive.ObjectID, reason string, results []models.QuestionResult, audits []models.TallyAudit) error {
+	event := ClosureEvent{
+		EventID: primitive.NewObjectID().Hex(),
+		Type: utils.CLOSURES_TOPIC,
+		SessionID: session.ID.Hex(),
+		Reason: reason,
+		Results: results,
+		Audits: audits,
+		Timestamp: time.Now(),
+	}
+	if !questionID.IsZero() {
+		event.QuestionID = questionID.Hex();
+	}
+
+	event.OrganizerID = session.OrganizerId.Hex();
+	for _, questionResults := range results {
+		for _, question := range session.Questions {
+			if question.ID.Hex() != questionResults.QuestionID {
+				continue;
+			}
+			if question.ResultsVisibility == utils.RESULTS_ORGANIZER_ONLY {
+				event.HiddenQuestionIDs = append(event.HiddenQuestionIDs, questionResults.QuestionID);
+			}
+			if session.HasPrivacy() {
+				event.PublicResults = append(event.PublicResults, publicResults(session, question, questionResults));
+			}
+		}
+	}
+
+	if err := Produce(ClosuresTopic, session.ID.Hex(), event); err != nil {
+		return fmt.Errorf("failed to emit closure event to Kafka: %v", err);
+	}
+	return nil;
+}
+
+var ErrClosingInProgress = errors.New("closing already in progress");
+
+// releaseLockScript deletes the lock only if it still holds our token, so a lock that expired and
+// was taken by someone else is left alone.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`);
+
+// acquireClosingLock makes sure concurrent consumers close (and broadcast) only once at a time.
+// The returned release frees the lock once the close is done, so a session reopened and closed
+// again is closed again. ErrClosingInProgress means someone else is closing it right now.
+func acquireClosingLock(sessionID, questionID primitive.ObjectID) (func(), error) {
+	redisDb := database.GetRedisInstance();
+	closingLockKey := fmt.Sprintf("closing_lock:%s:%s", sessionID.Hex(), questionID.Hex());
+	lockToken := primitive.NewObjectID().Hex();
+
+	acquired, err := redisDb.GetClient().SetNX(context.Background(), closingLockKey, lockToken, time.Minute).Result();
+	if err != nil {
+		log.Printf("Warning: closing lock unavailable, closing anyway: %v", err)
+		return func() {}, nil;
+	}
+	if !acquired {
+		return nil, ErrClosingInProgress;
+	}
+
+	return func() {
+		if err := releaseLockScript.Run(context.Background(), redisDb.GetClient(), []string{closingLockKey}, lockToken).Err(); err != nil {
+			log.Printf("Warning: Failed to release closing lock: %v", err)
+		}
+	}, nil;
+}
+
+// StartClosingScheduler closes sessions and questions whose deadline passed without any vote
+// coming in to trigger the evaluation.
+func StartClosingScheduler() {
+	ticker := time.NewTicker(closingSchedulerInterval);
+	defer ticker.Stop();
+
+	log.Println("Closing rules scheduler started...");
+
+	for range ticker.C {
+		sessions, err := services.FindSessionsWithDueDeadlines(time.Now());
+		if err != nil {
+			log.Printf("Closing scheduler: %v", err)
+			continue;
+		}
+
+		for i := range sessions {
+			session := &sessions[i];
+			for _, question := range session.Questions {
+				evaluateQuestionRules(session, question);
+			}
+			evaluateSessionRules(session);
+		}
+	}
+}
@@ -15,6 +15,10 @@ const (
 	VotesSubmittedTopic = "votes.submitted"
 	VotesProcessedTopic = "votes.processed"
 	ResultsUpdatedTopic = "votes.updated"
+	LeaderboardUpdatedTopic = "quiz.leaderboard"
+	QnAUpdatedTopic = "qna.updated"
+	ClosuresTopic = "sessions.closed"
+	ReceiptsTopic = "votes.receipts"
 )
 
 
@@ -96,4 +100,10 @@ func ProduceVoteSubmitted(voteEvent interface{}) error {
 
 
 	return Produce(utils.VOTES_SUBMITTED_TOPIC, sessionID, voteEvent);
-}
\ No newline at end of file
+}
+
+// ProduceSurveySubmitted publishes a survey submission keyed by session so it is
+// ordered with the single votes of the same session.
+func ProduceSurveySubmitted(surveyEvent SurveySubmittedEvent) error {
+	return Produce(utils.VOTES_SUBMITTED_TOPIC, surveyEvent.SessionID, surveyEvent);
+}
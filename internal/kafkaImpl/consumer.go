@@ -101,14 +101,51 @@ func isNonRetriableError(err error) bool {
         err.Error() == "session is not active" ||
         err.Error() == "session not found" ||
         err.Error() == "invalid session ID" ||
-        err.Error() == "invalid question ID")
+        err.Error() == "invalid question ID" ||
+        err.Error() == "question not found" ||
+        err.Error() == "question is closed" ||
+        err.Error() == "question is not open" ||
+        err.Error() == "vote not found" ||
+        err.Error() == "participant not on roster" ||
+        err.Error() == "vote cannot be retracted" ||
+        err.Error() == "question not shown to participant" ||
+        err.Error() == "option is full" ||
+        err.Error() == "invalid sealed ballot")
 }
 
 func processVoteMessage(msg kafka.Message) error {
+	var envelope struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal vote event: %v", err);
+	}
+
+	if envelope.Type == utils.SURVEY_SUBMITTED_EVENT {
+		var surveyEvent SurveySubmittedEvent;
+		if err := json.Unmarshal(msg.Value, &surveyEvent); err != nil {
+			return fmt.Errorf("failed to unmarshal survey event: %v", err);
+		}
+		if err := openSurveyEvent(&surveyEvent); err != nil {
+			return err;
+		}
+
+		log.Printf("Processing survey: eventId=%s, sessionId=%s, answers=%d", surveyEvent.EventID, surveyEvent.SessionID, len(surveyEvent.Answers));
+		return processSurvey(surveyEvent);
+	}
+
 	var voteEvent VoteSubmittedEvent;
 	if err := json.Unmarshal(msg.Value, &voteEvent); err != nil {
 		return fmt.Errorf("failed to unmarshal vote event: %v", err);
 	}
+	if err := openVoteEvent(&voteEvent); err != nil {
+		return err;
+	}
+
+	if voteEvent.Type == utils.VOTE_RETRACTED_EVENT {
+		log.Printf("Processing vote retraction: eventId=%s, sessionId=%s", voteEvent.EventID, voteEvent.SessionID);
+		return processRetraction(voteEvent);
+	}
 
 	log.Printf("Processing vote: eventId=%s, sessionId=%s", voteEvent.EventID, voteEvent.SessionID);
 
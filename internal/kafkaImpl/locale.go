@@ -0,0 +1,76 @@
+package kafkaImpl
+
+import (
+	"encoding/json"
+	"log"
+
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// translatedSession loads the session of a broadcast when it has translations, nil otherwise.
+func translatedSession(sessionID string) *models.Session {
+	id, err := primitive.ObjectIDFromHex(sessionID);
+	if err != nil {
+		return nil;
+	}
+	session, err := repository.GetSessionByID(id);
+	if err != nil {
+		log.Printf("Failed to load session %s for localized results: %v", sessionID, err)
+		return nil;
+	}
+	if len(session.Locales()) == 0 {
+		return nil;
+	}
+	return session;
+}
+
+// localizedMessages renders a websocket message once per locale of the session, with "results"
+// replaced by localize(locale). public is the message in the default locale. Only the texts differ,
+// counts and option indexes are the same in every variant.
+func localizedMessages(session *models.Session, wsMessage map[string]interface{}, public []byte, localize func(locale string) interface{}) map[string][]byte {
+	if session == nil {
+		return nil;
+	}
+
+	messages := make(map[string][]byte);
+	for _, locale := range session.Locales() {
+		if session.IsDefaultLocale(locale) {
+			messages[locale] = public;
+			continue;
+		}
+
+		variant := make(map[string]interface{}, len(wsMessage)+1);
+		for key, value := range wsMessage {
+			variant[key] = value;
+		}
+		variant["results"] = localize(locale);
+		variant["locale"] = locale;
+
+		messageJSON, err := json.Marshal(variant);
+		if err != nil {
+			log.Printf("Failed to marshal %s results: %v", locale, err)
+			continue;
+		}
+		messages[locale] = messageJSON;
+	}
+	return messages;
+}
+
+// localizeResultList renders the results of several questions of the session in one locale.
+func localizeResultList(session *models.Session, results []models.QuestionResult, locale string) []models.QuestionResult {
+	localized := make([]models.QuestionResult, len(results));
+	for i, result := range results {
+		localized[i] = result;
+		questionID, err := primitive.ObjectIDFromHex(result.QuestionID);
+		if err != nil {
+			continue;
+		}
+		if question, found := session.FindQuestion(questionID); found {
+			localized[i] = question.LocalizeResults(result, locale);
+		}
+	}
+	return localized;
+}
@@ -0,0 +1,51 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+)
+
+// addMatrixResults adds the distribution table of a matrix question, one row of column counts per row.
+func addMatrixResults(session *models.Session, question models.Question, results *models.QuestionResult) error {
+	if question.Type != utils.MATRIX {
+		return nil;
+	}
+
+	counts, err := services.CountRowAnswers(session.ID, question.ID);
+	if err != nil {
+		return err;
+	}
+
+	results.Rows = make([]models.RowResult, len(question.Rows));
+	for row, text := range question.Rows {
+		answered := 0;
+		positions := 0;
+		for column := range question.Options {
+			answered += counts[row][column];
+			positions += counts[row][column] * (column + 1);
+		}
+
+		rowResult := models.RowResult{
+			Index: row,
+			Text: text,
+			Options: make([]models.OptionCount, len(question.Options)),
+			Answered: answered,
+		};
+		for column, columnText := range question.Options {
+			rowResult.Options[column] = models.OptionCount{
+				Index: column,
+				Text: columnText,
+				Count: counts[row][column],
+			};
+			if answered > 0 {
+				rowResult.Options[column].Percentage = float64(counts[row][column]) / float64(answered) * 100;
+			}
+		}
+		if answered > 0 {
+			rowResult.Mean = float64(positions) / float64(answered);
+		}
+		results.Rows[row] = rowResult;
+	}
+	return nil;
+}
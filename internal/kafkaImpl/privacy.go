@@ -0,0 +1,207 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// publicResults derives the participant-facing results from the exact ones: laplace noise first,
+// then small-count suppression, then percentage rounding.
+func publicResults(session *models.Session, question models.Question, exact models.QuestionResult) models.QuestionResult {
+	privacy := session.Privacy;
+	public := exact;
+	public.Options = append([]models.OptionCount{}, exact.Options...);
+	if exact.Quorum != nil {
+		quorum := *exact.Quorum;
+		public.Quorum = &quorum;
+	}
+
+	if privacy.Epsilon > 0 {
+		spent, err := services.SpendPrivacyBudget(session.ID, privacy.Epsilon, privacy.EpsilonBudget);
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		if err != nil || !spent {
+			// no fresh release: repeating the last one leaks nothing new.
+			if cached, err := services.GetPublicResults(session.ID, question.ID); err == nil {
+				cached.Stale = true;
+				return *cached;
+			}
+			suppressAllCounts(&public);
+			public.Stale = true;
+			return public;
+		}
+		addLaplaceNoise(&public, question, privacy.Epsilon, session.Weighted);
+	}
+
+	if privacy.MinCount > 0 {
+		suppressSmallCounts(&public, privacy.MinCount);
+	}
+	if privacy.RoundPercentTo > 0 {
+		roundPercentages(&public, privacy.RoundPercentTo);
+	}
+
+	if privacy.Epsilon > 0 {
+		if err := services.CachePublicResults(session.ID, question.ID, public); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	return public;
+}
+
+// PublishedResults returns the results of a privacy session's question as participants see them,
+// without spending privacy budget: the last noisy release, or all counts suppressed before the first.
+func PublishedResults(session *models.Session, questionID primitive.ObjectID) (*models.QuestionResult, error) {
+	question, found := session.FindQuestion(questionID);
+	if !found {
+		return nil, fmt.Errorf("question not found");
+	}
+
+	if session.Privacy.Epsilon > 0 {
+		if cached, err := services.GetPublicResults(session.ID, questionID); err == nil {
+			return cached, nil;
+		}
+	}
+
+	exact, err := QuestionResults(session, questionID);
+	if err != nil {
+		return nil, err;
+	}
+	if session.Privacy.Epsilon > 0 {
+		public := *exact;
+		public.Options = append([]models.OptionCount{}, exact.Options...);
+		suppressAllCounts(&public);
+		public.Stale = true;
+		return &public, nil;
+	}
+	public := publicResults(session, question, *exact);
+	return &public, nil;
+}
+
+// addLaplaceNoise spends half of epsilon on the option counts and half on the voter count.
+// One multiple choice vote moves every option by at most one, so its sensitivity is the option count.
+func addLaplaceNoise(results *models.QuestionResult, question models.Question, epsilon float64, weighted bool) {
+	sensitivity := 1.0;
+	if question.Type == utils.MULTIPLE {
+		sensitivity = float64(len(question.Options));
+	}
+
+	totalVotes := 0;
+	for i := range results.Options {
+		option := &results.Options[i];
+		option.Count = noisyCount(option.Count, 2*sensitivity/epsilon);
+		totalVotes += option.Count;
+	}
+	results.TotalVotes = totalVotes;
+	results.VotersCount = noisyCount(results.VotersCount, 2/epsilon);
+
+	for i := range results.Options {
+		option := &results.Options[i];
+		option.Percentage = 0;
+		if totalVotes > 0 {
+			option.Percentage = float64(option.Count) / float64(totalVotes) * 100;
+		}
+		// voter weights are unbounded, weighted figures are not released with noise.
+		option.Weight = 0;
+		option.WeightedPercentage = 0;
+	}
+	results.TotalWeight = 0;
+	results.VotersWeight = 0;
+
+	if results.Quorum != nil {
+		if weighted {
+			results.Quorum = nil;
+		} else {
+			results.Quorum.Participating = float64(results.VotersCount);
+			results.Quorum.Reached = results.Quorum.Eligible > 0 && results.Quorum.Participating / results.Quorum.Eligible * 100 >= results.Quorum.RequiredPercent;
+		}
+	}
+	results.Noisy = true;
+}
+
+func noisyCount(count int, scale float64) int {
+	noisy := int(math.Round(float64(count) + laplace(scale)));
+	if noisy < 0 {
+		return 0;
+	}
+	return noisy;
+}
+
+// laplace samples Laplace(0, scale) by inverse transform, from a crypto random uniform.
+func laplace(scale float64) float64 {
+	var buf [8]byte;
+	rand.Read(buf[:]);
+	u := float64(binary.BigEndian.Uint64(buf[:])>>11)/float64(1<<53) - 0.5;
+	if u < 0 {
+		return scale * math.Log(1+2*u);
+	}
+	return -scale * math.Log(1-2*u);
+}
+
+// suppressSmallCounts hides counts between 1 and k-1. When a single option is hidden the next
+// smallest one is hidden too, otherwise it could be worked out from the total.
+func suppressSmallCounts(results *models.QuestionResult, minCount int) {
+	suppressed := 0;
+	for i := range results.Options {
+		if results.Options[i].Count > 0 && results.Options[i].Count < minCount {
+			suppressOption(&results.Options[i]);
+			suppressed++;
+		}
+	}
+
+	if suppressed == 1 {
+		visible := []int{};
+		for i := range results.Options {
+			if !results.Options[i].Suppressed && results.Options[i].Count > 0 {
+				visible = append(visible, i);
+			}
+		}
+		sort.Slice(visible, func(a, b int) bool {
+			return results.Options[visible[a]].Count < results.Options[visible[b]].Count;
+		})
+		if len(visible) > 0 {
+			suppressOption(&results.Options[visible[0]]);
+		}
+	}
+
+	if results.VotersCount < minCount {
+		suppressAllCounts(results);
+	}
+}
+
+func suppressAllCounts(results *models.QuestionResult) {
+	for i := range results.Options {
+		suppressOption(&results.Options[i]);
+	}
+	results.TotalVotes = 0;
+	results.VotersCount = 0;
+	results.TotalWeight = 0;
+	results.VotersWeight = 0;
+	results.Quorum = nil;
+}
+
+func suppressOption(option *models.OptionCount) {
+	option.Count = 0;
+	option.Percentage = 0;
+	option.Weight = 0;
+	option.WeightedPercentage = 0;
+	option.Suppressed = true;
+}
+
+func roundPercentages(results *models.QuestionResult, step float64) {
+	for i := range results.Options {
+		option := &results.Options[i];
+		option.Percentage = math.Round(option.Percentage/step) * step;
+		option.WeightedPercentage = math.Round(option.WeightedPercentage/step) * step;
+	}
+}
@@ -0,0 +1,43 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/realtime"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pulseStore backs the hub's live pulse questions with the session cache and the pulse_samples collection.
+type pulseStore struct{}
+
+func (pulseStore) PulseScale(sessionID, questionID string) (realtime.PulseScale, bool) {
+	sessionObjID, err := primitive.ObjectIDFromHex(sessionID);
+	if err != nil {
+		return realtime.PulseScale{}, false;
+	}
+	questionObjID, err := primitive.ObjectIDFromHex(questionID);
+	if err != nil {
+		return realtime.PulseScale{}, false;
+	}
+
+	session, err := repository.GetSessionByID(sessionObjID);
+	if err != nil || session.Status != utils.ACTIVE {
+		return realtime.PulseScale{}, false;
+	}
+	question, found := session.FindQuestion(questionObjID);
+	if !found || question.Type != utils.PULSE || question.ClosedAt != nil {
+		return realtime.PulseScale{}, false;
+	}
+	return realtime.PulseScale{Min: float64(question.SliderMin), Max: float64(question.SliderMax)}, true;
+}
+
+func (pulseStore) SavePulseSample(sample models.PulseSample) {
+	if err := services.SavePulseSample(sample); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PublishQnAUpdate emits the ranked Q&A of a session after any change (new question, upvote, moderation).
+func PublishQnAUpdate(sessionID primitive.ObjectID) error {
+	session, err := repository.GetSessionByID(sessionID);
+	if err != nil {
+		return fmt.Errorf("session not found");
+	}
+
+	questions, err := services.ListAudienceQuestions(sessionID, true);
+	if err != nil {
+		return fmt.Errorf("failed to load audience questions: %v", err);
+	}
+
+	event := QnAUpdatedEvent{
+		EventID: primitive.NewObjectID().Hex(),
+		Type: utils.QNA_UPDATED_TOPIC,
+		SessionID: sessionID.Hex(),
+		Questions: questions,
+		OrganizerID: session.OrganizerId.Hex(),
+		Timestamp: time.Now(),
+	}
+
+	if err := Produce(QnAUpdatedTopic, sessionID.Hex(), event); err != nil {
+		return fmt.Errorf("failed to emit Q&A event to Kafka: %v", err);
+	}
+
+	log.Printf("Q&A update published: session=%s, questions=%d", sessionID.Hex(), len(questions))
+	return nil;
+}
+
+// publicAudienceQuestions keeps only what participants may see, in the same order.
+func publicAudienceQuestions(questions []models.AudienceQuestion) []models.AudienceQuestion {
+	public := []models.AudienceQuestion{};
+	for _, q := range questions {
+		if q.Status == utils.QNA_APPROVED || q.Status == utils.QNA_ANSWERED {
+			q.ParticipantID = "";
+			public = append(public, q);
+		}
+	}
+	return public;
+}
@@ -0,0 +1,114 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// scoreAnswer returns whether the selection matches the correct options and the points earned.
+// Correct answers get the question points plus a speed bonus of up to half the points,
+// decaying linearly over the time limit since the question was opened.
+func scoreAnswer(question models.Question, selected []int, answeredAt time.Time) (bool, int) {
+	if !isCorrectSelection(question.CorrectOptions, selected) {
+		return false, 0;
+	}
+
+	points := question.Points;
+	if points <= 0 {
+		points = utils.QUIZ_DEFAULT_POINTS;
+	}
+
+	if question.OpenedAt == nil {
+		return true, points;
+	}
+
+	timeLimit := time.Duration(question.TimeLimitSeconds) * time.Second;
+	if timeLimit <= 0 {
+		timeLimit = time.Duration(utils.QUIZ_DEFAULT_TIME_LIMIT_SECONDS) * time.Second;
+	}
+
+	elapsed := answeredAt.Sub(*question.OpenedAt);
+	if elapsed < 0 {
+		elapsed = 0;
+	}
+
+	bonus := 0;
+	if elapsed < timeLimit {
+		remaining := float64(timeLimit - elapsed) / float64(timeLimit);
+		bonus = int(float64(points) / 2 * remaining);
+	}
+
+	return true, points + bonus;
+}
+
+func isCorrectSelection(correct, selected []int) bool {
+	if len(correct) == 0 || len(correct) != len(selected) {
+		return false;
+	}
+
+	want := append([]int(nil), correct...);
+	got := append([]int(nil), selected...);
+	sort.Ints(want);
+	sort.Ints(got);
+
+	for i := range want {
+		if want[i] != got[i] {
+			return false;
+		}
+	}
+	return true;
+}
+
+// CloseOpenQuizQuestions ends the questions of a quiz still open when the organizer opens the
+// next one, so the leaderboard is published after every question and not only on explicit closes.
+func CloseOpenQuizQuestions(session *models.Session, nextQuestionID primitive.ObjectID) error {
+	if !session.IsQuiz() {
+		return nil;
+	}
+
+	for _, question := range session.Questions {
+		if question.ID == nextQuestionID || question.OpenedAt == nil || question.ClosedAt != nil {
+			continue;
+		}
+		if err := CloseQuestion(session, question, "next_question"); err != nil {
+			return err;
+		}
+	}
+	return nil;
+}
+
+// PublishLeaderboard emits the current top-N standings of a quiz so every client gets them,
+// right after a question is closed.
+func PublishLeaderboard(sessionID, questionID primitive.ObjectID) error {
+	leaderboard, err := services.GetLeaderboard(sessionID, utils.QUIZ_LEADERBOARD_SIZE);
+	if err != nil {
+		return fmt.Errorf("failed to load leaderboard: %v", err);
+	}
+
+	if !questionID.IsZero() {
+		leaderboard.QuestionID = questionID.Hex();
+	}
+
+	event := LeaderboardUpdatedEvent{
+		EventID: primitive.NewObjectID().Hex(),
+		Type: utils.LEADERBOARD_UPDATED_TOPIC,
+		SessionID: sessionID.Hex(),
+		Leaderboard: *leaderboard,
+		Timestamp: time.Now(),
+	}
+
+	if err := Produce(LeaderboardUpdatedTopic, sessionID.Hex(), event); err != nil {
+		return fmt.Errorf("failed to emit leaderboard event to Kafka: %v", err);
+	}
+
+	log.Printf("Leaderboard published: session=%s, question=%s", sessionID.Hex(), questionID.Hex())
+	return nil;
+}
@@ -0,0 +1,65 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"testing"
+	"time"
+)
+
+func TestScoreAnswer(t *testing.T) {
+	opened := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC);
+	timed := func(points, limitSeconds int) models.Question {
+		return models.Question{
+			CorrectOptions: []int{1},
+			Points: points,
+			TimeLimitSeconds: limitSeconds,
+			OpenedAt: &opened,
+		};
+	}
+
+	tests := []struct {
+		name string
+		question models.Question
+		selected []int
+		answeredAt time.Time
+		wantCorrect bool
+		wantScore int
+	}{
+		{"wrong option", timed(100, 20), []int{0}, opened, false, 0},
+		{"no selection", timed(100, 20), []int{}, opened, false, 0},
+		{"no correct options", models.Question{Points: 100}, []int{0}, opened, false, 0},
+		{"not timed", models.Question{CorrectOptions: []int{1}, Points: 100}, []int{1}, opened, true, 100},
+		{"default points", models.Question{CorrectOptions: []int{1}}, []int{1}, opened, true, utils.QUIZ_DEFAULT_POINTS},
+		{"instant answer gets full bonus", timed(100, 20), []int{1}, opened, true, 150},
+		{"half time gets half bonus", timed(100, 20), []int{1}, opened.Add(10 * time.Second), true, 125},
+		{"at the limit gets no bonus", timed(100, 20), []int{1}, opened.Add(20 * time.Second), true, 100},
+		{"late answer keeps base points", timed(100, 20), []int{1}, opened.Add(time.Minute), true, 100},
+		{"clock skew counts as instant", timed(100, 20), []int{1}, opened.Add(-time.Second), true, 150},
+		{"multiple correct in any order", models.Question{CorrectOptions: []int{0, 2}, Points: 10}, []int{2, 0}, opened, true, 10},
+		{"multiple correct missing one", models.Question{CorrectOptions: []int{0, 2}, Points: 10}, []int{0}, opened, false, 0},
+		{"multiple correct with extra", models.Question{CorrectOptions: []int{0, 2}, Points: 10}, []int{0, 1, 2}, opened, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			correct, score := scoreAnswer(tt.question, tt.selected, tt.answeredAt);
+			if correct != tt.wantCorrect || score != tt.wantScore {
+				t.Errorf("scoreAnswer() = (%v, %d), want (%v, %d)", correct, score, tt.wantCorrect, tt.wantScore);
+			}
+		})
+	}
+}
+
+func TestScoreAnswerDefaultTimeLimit(t *testing.T) {
+	opened := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC);
+	question := models.Question{CorrectOptions: []int{0}, Points: 100, OpenedAt: &opened};
+
+	limit := time.Duration(utils.QUIZ_DEFAULT_TIME_LIMIT_SECONDS) * time.Second;
+	if _, score := scoreAnswer(question, []int{0}, opened.Add(limit)); score != 100 {
+		t.Errorf("score at the default limit = %d, want 100", score);
+	}
+	if _, score := scoreAnswer(question, []int{0}, opened); score != 150 {
+		t.Errorf("instant score = %d, want 150", score);
+	}
+}
@@ -0,0 +1,160 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+	"RealTimePoll/pkg/merkle"
+	"RealTimePoll/pkg/receipt"
+
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sealBallot sets the ballot hash of the vote and returns the signed receipt for the participant.
+// The nonce only travels in the receipt, so the hash alone does not reveal the selection.
+func sealBallot(vote *models.Vote, voteID string) models.VoteReceipt {
+	nonceBytes := make([]byte, 16);
+	rand.Read(nonceBytes);
+
+	voteReceipt := models.VoteReceipt{
+		VoteID: voteID,
+		SessionID: vote.SessionID.Hex(),
+		QuestionID: vote.QuestionID.Hex(),
+		SelectedOptions: vote.SelectedOptions,
+		RowAnswers: vote.RowAnswers,
+		Allocation: vote.Allocation,
+		Availability: vote.Availability,
+		Nonce: hex.EncodeToString(nonceBytes),
+		IssuedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	voteReceipt.BallotHash = BallotHash(voteReceipt.SessionID, voteReceipt.QuestionID, BallotAnswer(voteReceipt), voteReceipt.Nonce);
+	voteReceipt.Signature = receipt.Sign(ReceiptPayload(voteReceipt));
+
+	vote.BallotHash = voteReceipt.BallotHash;
+	return voteReceipt;
+}
+
+// BallotHash is sha256 over "sessionId|questionId|answer|nonce", hex encoded.
+func BallotHash(sessionID, questionID, answer, nonce string) string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{sessionID, questionID, answer, nonce}, "|")));
+	return hex.EncodeToString(hash[:]);
+}
+
+// BallotAnswer is the answer part of a ballot hash. Answers that are more than a set of options
+// keep their order: "rows:" and the column of each matrix row, "points:" and the points of each
+// option, "availability:" and the answer of each slot. Other selections are sorted options.
+func BallotAnswer(voteReceipt models.VoteReceipt) string {
+	switch {
+	case len(voteReceipt.RowAnswers) > 0:
+		return "rows:" + joinInts(voteReceipt.RowAnswers);
+	case len(voteReceipt.Allocation) > 0:
+		return "points:" + joinInts(voteReceipt.Allocation);
+	case len(voteReceipt.Availability) > 0:
+		return "availability:" + strings.Join(voteReceipt.Availability, ",");
+	}
+
+	options := append([]int{}, voteReceipt.SelectedOptions...);
+	sort.Ints(options);
+	return joinInts(options);
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values));
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value);
+	}
+	return strings.Join(parts, ",");
+}
+
+// ReceiptPayload is the byte string the receipt signature covers.
+func ReceiptPayload(voteReceipt models.VoteReceipt) []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s|%s|%d", voteReceipt.VoteID, voteReceipt.SessionID, voteReceipt.QuestionID, voteReceipt.BallotHash, voteReceipt.IssuedAt.Unix()));
+}
+
+// TallyAuditPayload is the byte string the signature of a published merkle root covers.
+func TallyAuditPayload(audit models.TallyAudit) []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s|%d", audit.SessionID.Hex(), audit.QuestionID.Hex(), audit.MerkleRoot, audit.LeafCount));
+}
+
+// deliverReceipt stores the receipt and pushes it to the participant once the vote is saved.
+// Secret ballot receipts leave the answer out: next to the participant id it would say who
+// voted what. The voter still checks the ballot hash with their own selection and the nonce.
+func deliverReceipt(session *models.Session, voteReceipt models.VoteReceipt, participantID string) {
+	if session.SecretBallot {
+		voteReceipt.SelectedOptions = nil;
+		voteReceipt.RowAnswers = nil;
+		voteReceipt.Allocation = nil;
+		voteReceipt.Availability = nil;
+	}
+
+	if err := services.SaveVoteReceipt(voteReceipt, participantID); err != nil {
+		log.Printf("Warning: Failed to store receipt of vote %s: %v", voteReceipt.VoteID, err)
+	}
+
+	event := ReceiptIssuedEvent{
+		EventID: primitive.NewObjectID().Hex(),
+		Type: utils.RECEIPTS_TOPIC,
+		SessionID: voteReceipt.SessionID,
+		ParticipantID: participantID,
+		Receipt: voteReceipt,
+		Timestamp: time.Now(),
+	}
+	if err := Produce(ReceiptsTopic, voteReceipt.SessionID, event); err != nil {
+		log.Printf("Warning: Failed to emit receipt of vote %s: %v", voteReceipt.VoteID, err)
+	}
+}
+
+// MerkleLeaves decodes sorted hex ballot hashes into merkle leaves.
+func MerkleLeaves(hashes []string) ([][]byte, error) {
+	leaves := make([][]byte, len(hashes));
+	for i, hash := range hashes {
+		leaf, err := hex.DecodeString(hash);
+		if err != nil {
+			return nil, fmt.Errorf("invalid ballot hash %s", hash);
+		}
+		leaves[i] = leaf;
+	}
+	return leaves, nil;
+}
+
+// publishTallyAudits computes and stores the merkle root of every question of a closed session.
+func publishTallyAudits(session *models.Session) ([]models.TallyAudit, error) {
+	audits := []models.TallyAudit{};
+	for _, question := range session.Questions {
+		hashes, err := services.GetBallotHashes(session.ID, question.ID);
+		if err != nil {
+			return nil, err;
+		}
+
+		leaves, err := MerkleLeaves(hashes);
+		if err != nil {
+			return nil, err;
+		}
+
+		audit := models.TallyAudit{
+			ID: primitive.NewObjectID(),
+			SessionID: session.ID,
+			QuestionID: question.ID,
+			MerkleRoot: hex.EncodeToString(merkle.Root(leaves)),
+			LeafCount: len(leaves),
+			PublishedAt: time.Now(),
+		}
+		audit.Signature = receipt.Sign(TallyAuditPayload(audit));
+		audits = append(audits, audit);
+	}
+
+	if err := services.SaveTallyAudits(audits); err != nil {
+		return nil, err;
+	}
+	return audits, nil;
+}
@@ -0,0 +1,70 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+
+	"testing"
+)
+
+func TestBallotAnswer(t *testing.T) {
+	tests := []struct {
+		name string
+		receipt models.VoteReceipt
+		want string
+	}{
+		{"no selection", models.VoteReceipt{}, ""},
+		{"single option", models.VoteReceipt{SelectedOptions: []int{2}}, "2"},
+		{"options sorted", models.VoteReceipt{SelectedOptions: []int{3, 0, 1}}, "0,1,3"},
+		{"matrix rows in order", models.VoteReceipt{SelectedOptions: []int{2, 0}, RowAnswers: []int{2, 0}}, "rows:2,0"},
+		{"allocation points per option", models.VoteReceipt{SelectedOptions: []int{0, 2}, Allocation: []int{70, 0, 30}}, "points:70,0,30"},
+		{"availability per slot", models.VoteReceipt{SelectedOptions: []int{0, 1}, Availability: []string{"yes", "if_need_be", "no"}}, "availability:yes,if_need_be,no"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BallotAnswer(tt.receipt); got != tt.want {
+				t.Errorf("BallotAnswer() = %q, want %q", got, tt.want);
+			}
+		})
+	}
+}
+
+func TestBallotHashTellsBallotsApart(t *testing.T) {
+	hash := func(voteReceipt models.VoteReceipt) string {
+		return BallotHash("s", "q", BallotAnswer(voteReceipt), "n");
+	}
+
+	tests := []struct {
+		name string
+		a, b models.VoteReceipt
+		same bool
+	}{
+		{"option order does not matter", models.VoteReceipt{SelectedOptions: []int{0, 2}}, models.VoteReceipt{SelectedOptions: []int{2, 0}}, true},
+		{"different options", models.VoteReceipt{SelectedOptions: []int{0}}, models.VoteReceipt{SelectedOptions: []int{1}}, false},
+		{"matrix rows swapped", models.VoteReceipt{RowAnswers: []int{0, 2}}, models.VoteReceipt{RowAnswers: []int{2, 0}}, false},
+		{"matrix against plain options", models.VoteReceipt{RowAnswers: []int{0, 2}}, models.VoteReceipt{SelectedOptions: []int{0, 2}}, false},
+		{"same options, other points", models.VoteReceipt{SelectedOptions: []int{0, 2}, Allocation: []int{70, 0, 30}}, models.VoteReceipt{SelectedOptions: []int{0, 2}, Allocation: []int{30, 0, 70}}, false},
+		{"same slots, yes against if need be", models.VoteReceipt{SelectedOptions: []int{0}, Availability: []string{"yes", "no"}}, models.VoteReceipt{SelectedOptions: []int{0}, Availability: []string{"if_need_be", "no"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := hash(tt.a) == hash(tt.b); same != tt.same {
+				t.Errorf("hashes equal = %v, want %v", same, tt.same);
+			}
+		})
+	}
+}
+
+func TestBallotHashDependsOnEveryPart(t *testing.T) {
+	base := BallotHash("s", "q", "1", "n");
+	others := []string{
+		BallotHash("s2", "q", "1", "n"),
+		BallotHash("s", "q2", "1", "n"),
+		BallotHash("s", "q", "2", "n"),
+		BallotHash("s", "q", "1", "n2"),
+	};
+	for i, other := range others {
+		if other == base {
+			t.Errorf("changing part %d keeps the hash", i);
+		}
+	}
+}
@@ -8,16 +8,22 @@ import (
 	"time"
 	"github.com/segmentio/kafka-go"
 
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
 	"RealTimePoll/internal/realtime"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
 	"RealTimePoll/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 
-// This method consumes voted.updated events and broadcasts via websocket.
+// This method consumes voted.updated (and other realtime) events and broadcasts via websocket.
 func StartResultsBroadcaster(hub *realtime.Hub) {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers: []string{utils.KAFKA_CONNECTION},
-        Topic:   ResultsUpdatedTopic,
+        GroupTopics: []string{ResultsUpdatedTopic, LeaderboardUpdatedTopic, QnAUpdatedTopic, ClosuresTopic, ReceiptsTopic},
         GroupID: utils.KAFKA_RESULTS_BROADCASTER_GROUP, // Different consumer group
         MinBytes: 10e3, // 10KB
         MaxBytes: 10e6, // 10MB
@@ -39,9 +45,22 @@ func StartResultsBroadcaster(hub *realtime.Hub) {
 		 log.Printf("Received results update: topic=%s partition=%d offset=%d", 
             msg.Topic, msg.Partition, msg.Offset);
 
-		// Process the results event.
-		if err := processResultsMessage(hub, msg); err != nil {
-            log.Printf("Failed to process results event: %v", err)
+		// Process the event based on the topic it came from.
+		switch msg.Topic {
+		case LeaderboardUpdatedTopic:
+			err = processLeaderboardMessage(hub, msg);
+		case QnAUpdatedTopic:
+			err = processQnAMessage(hub, msg);
+		case ClosuresTopic:
+			err = processClosureMessage(hub, msg);
+		case ReceiptsTopic:
+			err = processReceiptMessage(hub, msg);
+		default:
+			err = processResultsMessage(hub, msg);
+		}
+
+		if err != nil {
+            log.Printf("Failed to process %s event: %v", msg.Topic, err)
             // Continue processing other messages
         }
 	 }
@@ -78,8 +97,44 @@ func broadcastResultsUpdate(hub *realtime.Hub, resultsEvent ResultsUpdatedEvent)
 		return fmt.Errorf("failed to marshal WebSocket message: %v", err);
 	}
 
-	// broadcasting to all clients in the session - poll
-	hub.BroadcastToSession(resultsEvent.SessionID, messageJSON);
+	// a retracted participant no longer sees after_vote results until they vote again.
+	if resultsEvent.RetractedBy != "" {
+		hub.ClearVoted(resultsEvent.SessionID, resultsEvent.QuestionID, resultsEvent.RetractedBy);
+	}
+	if resultsEvent.Visibility == utils.RESULTS_AFTER_VOTE {
+		markVotedParticipants(hub, resultsEvent.SessionID, resultsEvent.QuestionID);
+	}
+
+	// privacy sessions: exact counts to the organizer, protected counts to everyone else.
+	publicJSON, exactJSON := messageJSON, []byte(nil);
+	if resultsEvent.PublicResults != nil {
+		wsMessage["results"] = resultsEvent.PublicResults;
+		publicJSON, err = json.Marshal(wsMessage);
+		if err != nil {
+			return fmt.Errorf("failed to marshal WebSocket message: %v", err);
+		}
+		exactJSON = messageJSON;
+	}
+
+	// translated sessions: participants get the public results in their language.
+	publicResults := resultsEvent.Results;
+	if resultsEvent.PublicResults != nil {
+		publicResults = *resultsEvent.PublicResults;
+	}
+	var localizedJSON map[string][]byte;
+	if session := translatedSession(resultsEvent.SessionID); session != nil {
+		if questionID, err := primitive.ObjectIDFromHex(resultsEvent.QuestionID); err == nil {
+			if question, found := session.FindQuestion(questionID); found {
+				wsMessage["results"] = publicResults;
+				localizedJSON = localizedMessages(session, wsMessage, publicJSON, func(locale string) interface{} {
+					return question.LocalizeResults(publicResults, locale);
+				});
+			}
+		}
+	}
+
+	// the hub routes per client by the question's results visibility.
+	hub.BroadcastQuestionResults(resultsEvent.SessionID, resultsEvent.QuestionID, resultsEvent.Visibility, resultsEvent.OrganizerID, publicJSON, exactJSON, localizedJSON);
 
 	log.Printf("Results broadcasted via WebSocket: session=%s, question=%s, clients_notified=true", 
         resultsEvent.SessionID, resultsEvent.QuestionID)
@@ -87,12 +142,243 @@ func broadcastResultsUpdate(hub *realtime.Hub, resultsEvent ResultsUpdatedEvent)
     return nil
 }
 
+// markVotedParticipants looks up the votes of connected participants whose vote status is
+// unknown yet, so after_vote results reach everyone who currently holds a vote. Secret ballots
+// keep no participant on the vote, so their vote locks are checked instead.
+func markVotedParticipants(hub *realtime.Hub, sessionID, questionID string) {
+	participantIDs := hub.UnvotedParticipantIDs(sessionID, questionID);
+	if len(participantIDs) == 0 {
+		return;
+	}
+
+	sessionObjID, err := primitive.ObjectIDFromHex(sessionID);
+	if err != nil {
+		return;
+	}
+	questionObjID, err := primitive.ObjectIDFromHex(questionID);
+	if err != nil {
+		return;
+	}
+
+	session, err := repository.GetSessionByID(sessionObjID);
+	if err != nil {
+		log.Printf("Failed to load session %s for results visibility: %v", sessionID, err)
+		return;
+	}
+
+	if !session.SecretBallot {
+		voted, err := services.VotedParticipantIDs(sessionObjID, questionObjID, participantIDs);
+		if err != nil {
+			log.Printf("Failed to load voted participants of session %s: %v", sessionID, err)
+			return;
+		}
+		hub.MarkVoted(sessionID, questionID, voted);
+		return;
+	}
+
+	redisDb := database.GetRedisInstance();
+	voted := []string{};
+	for _, participantID := range participantIDs {
+		lockKey := voteLockKey(sessionObjID, questionObjID, dedupID(session, questionObjID, participantID));
+		exists, err := redisDb.GetClient().Exists(context.Background(), lockKey).Result();
+		if err == nil && exists > 0 {
+			voted = append(voted, participantID);
+		}
+	}
+	hub.MarkVoted(sessionID, questionID, voted);
+}
+
+// processLeaderboardMessage broadcasts the top-N standings to the session and
+// sends every connected participant their own rank.
+func processLeaderboardMessage(hub *realtime.Hub, msg kafka.Message) error {
+	var leaderboardEvent LeaderboardUpdatedEvent
+	if err := json.Unmarshal(msg.Value, &leaderboardEvent); err != nil {
+		return fmt.Errorf("failed to unmarshal leaderboard event: %v", err)
+	}
+
+	wsMessage := map[string]interface{}{
+		"type":        "leaderboard",
+		"sessionId":   leaderboardEvent.SessionID,
+		"leaderboard": leaderboardEvent.Leaderboard,
+		"timestamp":   leaderboardEvent.Timestamp,
+		"eventId":     leaderboardEvent.EventID,
+	}
+
+	messageJSON, err := json.Marshal(wsMessage);
+	if err != nil {
+		return fmt.Errorf("failed to marshal WebSocket message: %v", err);
+	}
+	hub.BroadcastToSession(leaderboardEvent.SessionID, messageJSON);
+
+	sessionID, err := primitive.ObjectIDFromHex(leaderboardEvent.SessionID);
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %v", err);
+	}
+
+	for _, participantID := range hub.SessionUserIDs(leaderboardEvent.SessionID, "participant") {
+		standing, err := services.GetParticipantStanding(sessionID, participantID);
+		if err != nil {
+			log.Printf("Failed to get standing for participant %s: %v", participantID, err)
+			continue;
+		}
+
+		rankMessage := map[string]interface{}{
+			"type":              "leaderboard_rank",
+			"sessionId":         leaderboardEvent.SessionID,
+			"questionId":        leaderboardEvent.Leaderboard.QuestionID,
+			"standing":          standing, // null until the participant answered
+			"totalParticipants": leaderboardEvent.Leaderboard.TotalParticipants,
+			"timestamp":         leaderboardEvent.Timestamp,
+		}
+
+		rankJSON, err := json.Marshal(rankMessage);
+		if err != nil {
+			continue;
+		}
+		hub.SendToUser(leaderboardEvent.SessionID, participantID, rankJSON);
+	}
+
+	log.Printf("Leaderboard broadcasted via WebSocket: session=%s", leaderboardEvent.SessionID)
+	return nil
+}
+
+// processQnAMessage streams the ranked Q&A: participants get the approved list,
+// organizers get everything including the moderation queue.
+func processQnAMessage(hub *realtime.Hub, msg kafka.Message) error {
+	var qnaEvent QnAUpdatedEvent
+	if err := json.Unmarshal(msg.Value, &qnaEvent); err != nil {
+		return fmt.Errorf("failed to unmarshal Q&A event: %v", err)
+	}
+
+	wsMessage := map[string]interface{}{
+		"type":      "qna_updated",
+		"sessionId": qnaEvent.SessionID,
+		"questions": publicAudienceQuestions(qnaEvent.Questions),
+		"timestamp": qnaEvent.Timestamp,
+		"eventId":   qnaEvent.EventID,
+	}
+	publicJSON, err := json.Marshal(wsMessage);
+	if err != nil {
+		return fmt.Errorf("failed to marshal WebSocket message: %v", err);
+	}
+
+	// the moderation queue only goes to the session's organizer, whose id comes from a verified token.
+	wsMessage["questions"] = qnaEvent.Questions;
+	organizerJSON, err := json.Marshal(wsMessage);
+	if err != nil {
+		return fmt.Errorf("failed to marshal WebSocket message: %v", err);
+	}
+	hub.BroadcastWithOrganizerView(qnaEvent.SessionID, qnaEvent.OrganizerID, publicJSON, organizerJSON, nil);
+
+	log.Printf("Q&A broadcasted via WebSocket: session=%s", qnaEvent.SessionID)
+	return nil
+}
+
+// processClosureMessage tells every client that a session or question closed, with final results.
+func processClosureMessage(hub *realtime.Hub, msg kafka.Message) error {
+	var closureEvent ClosureEvent
+	if err := json.Unmarshal(msg.Value, &closureEvent); err != nil {
+		return fmt.Errorf("failed to unmarshal closure event: %v", err)
+	}
+
+	messageType := "session_closed"
+	if closureEvent.QuestionID != "" {
+		messageType = "question_closed"
+	}
+
+	wsMessage := map[string]interface{}{
+		"type":       messageType,
+		"sessionId":  closureEvent.SessionID,
+		"questionId": closureEvent.QuestionID,
+		"reason":     closureEvent.Reason,
+		"results":    closureEvent.Results,
+		"audits":     closureEvent.Audits,
+		"timestamp":  closureEvent.Timestamp,
+		"eventId":    closureEvent.EventID,
+	}
+
+	messageJSON, err := json.Marshal(wsMessage);
+	if err != nil {
+		return fmt.Errorf("failed to marshal WebSocket message: %v", err);
+	}
+
+	publicJSON, exactJSON, publicResults := messageJSON, []byte(nil), closureEvent.Results;
+	if closureEvent.PublicResults != nil || len(closureEvent.HiddenQuestionIDs) > 0 {
+		if closureEvent.PublicResults != nil {
+			publicResults = closureEvent.PublicResults;
+		}
+		publicResults = withoutHiddenResults(publicResults, closureEvent.HiddenQuestionIDs);
+		wsMessage["results"] = publicResults;
+
+		publicJSON, err = json.Marshal(wsMessage);
+		if err != nil {
+			return fmt.Errorf("failed to marshal WebSocket message: %v", err);
+		}
+		exactJSON = messageJSON;
+	}
+
+	var localizedJSON map[string][]byte;
+	if session := translatedSession(closureEvent.SessionID); session != nil {
+		localizedJSON = localizedMessages(session, wsMessage, publicJSON, func(locale string) interface{} {
+			return localizeResultList(session, publicResults, locale);
+		});
+	}
+	hub.BroadcastWithOrganizerView(closureEvent.SessionID, closureEvent.OrganizerID, publicJSON, exactJSON, localizedJSON);
+
+	log.Printf("Closure broadcasted via WebSocket: session=%s, question=%s, reason=%s",
+		closureEvent.SessionID, closureEvent.QuestionID, closureEvent.Reason)
+	return nil
+}
+
+func withoutHiddenResults(results []models.QuestionResult, hiddenQuestionIDs []string) []models.QuestionResult {
+	hidden := make(map[string]bool);
+	for _, questionID := range hiddenQuestionIDs {
+		hidden[questionID] = true;
+	}
+
+	visible := []models.QuestionResult{};
+	for _, questionResults := range results {
+		if !hidden[questionResults.QuestionID] {
+			visible = append(visible, questionResults);
+		}
+	}
+	return visible;
+}
+
+// processReceiptMessage sends a vote receipt to the participant who cast the vote only.
+func processReceiptMessage(hub *realtime.Hub, msg kafka.Message) error {
+	var receiptEvent ReceiptIssuedEvent
+	if err := json.Unmarshal(msg.Value, &receiptEvent); err != nil {
+		return fmt.Errorf("failed to unmarshal receipt event: %v", err)
+	}
+
+	wsMessage := map[string]interface{}{
+		"type":      "vote_receipt",
+		"sessionId": receiptEvent.SessionID,
+		"receipt":   receiptEvent.Receipt,
+		"timestamp": receiptEvent.Timestamp,
+		"eventId":   receiptEvent.EventID,
+	}
+
+	messageJSON, err := json.Marshal(wsMessage);
+	if err != nil {
+		return fmt.Errorf("failed to marshal WebSocket message: %v", err);
+	}
+	hub.SendToUser(receiptEvent.SessionID, receiptEvent.ParticipantID, messageJSON);
+	return nil
+}
+
 func StartAllConsumer(hub *realtime.Hub) {
+	hub.SetPulseStore(pulseStore{})
+
 	go func() {
         log.Println("Starting vote processor consumer...")
         StartVoteConsumer()
     }()
 
+    // Start closing rules scheduler (deadlines)
+    go StartClosingScheduler()
+
     // Start results broadcaster consumer
     go func() {
         log.Println("Starting results broadcaster consumer...")
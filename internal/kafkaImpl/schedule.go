@@ -0,0 +1,106 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// addScheduleResults splits the availability of every slot into yes and if-need-be, ranks the
+// slots and, in identified sessions, lists who is available.
+func addScheduleResults(session *models.Session, question models.Question, results *models.QuestionResult) error {
+	if question.Type != utils.SCHEDULE {
+		return nil;
+	}
+
+	answers, err := services.GetSlotAnswers(session.ID, question.ID);
+	if err != nil {
+		return err;
+	}
+
+	names := map[string]string{};
+	if session.IsIdentified() {
+		roster, err := services.GetRoster(session.ID);
+		if err != nil {
+			return err;
+		}
+		for _, entry := range roster {
+			names[entry.ID.Hex()] = rosterName(entry);
+		}
+	}
+
+	for _, answer := range answers {
+		for slot, availability := range answer.Availability {
+			if slot >= len(results.Options) || availability == utils.AVAILABILITY_NO {
+				continue;
+			}
+			if availability == utils.AVAILABILITY_IF_NEED_BE {
+				results.Options[slot].IfNeedBe++;
+			}
+			if session.IsIdentified() && !answer.ParticipantID.IsZero() {
+				participantID := answer.ParticipantID.Hex();
+				results.Options[slot].Available = append(results.Options[slot].Available, models.SlotParticipant{
+					ParticipantID: participantID,
+					Name:          names[participantID],
+					Answer:        availability,
+				});
+			}
+		}
+	}
+
+	results.Ranking = rankSlots(question, results.Options);
+	return nil;
+}
+
+// rankSlots orders slots by participants available, then by clear yes answers, then by start time.
+func rankSlots(question models.Question, options []models.OptionCount) []int {
+	ranking := make([]int, len(options));
+	for i := range ranking {
+		ranking[i] = i;
+	}
+
+	sort.SliceStable(ranking, func(a, b int) bool {
+		first, second := options[ranking[a]], options[ranking[b]];
+		if first.Count != second.Count {
+			return first.Count > second.Count;
+		}
+		if first.Count - first.IfNeedBe != second.Count - second.IfNeedBe {
+			return first.Count - first.IfNeedBe > second.Count - second.IfNeedBe;
+		}
+		return question.Slots[ranking[a]].Start.Before(question.Slots[ranking[b]].Start);
+	});
+	return ranking;
+}
+
+func rosterName(entry models.RosterEntry) string {
+	switch {
+	case entry.Name != "":
+		return entry.Name;
+	case entry.Email != "":
+		return entry.Email;
+	default:
+		return entry.ExternalID;
+	}
+}
+
+// QuestionResults returns the results of a question from the cache, computing them when missing.
+func QuestionResults(session *models.Session, questionID primitive.ObjectID) (*models.QuestionResult, error) {
+	if cached, err := GetCachedResults(session.ID, questionID); err == nil && cached != nil {
+		return cached, nil;
+	}
+
+	question, found := session.FindQuestion(questionID);
+	if !found {
+		return nil, fmt.Errorf("question not found");
+	}
+	results, err := buildQuestionResults(session, question);
+	if err != nil {
+		return nil, err;
+	}
+	return &results, nil;
+}
@@ -0,0 +1,152 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// dedupID is the participant identifier used in vote locks. Secret ballot sessions use a
+// keyed hash so redis never holds who voted on what in clear.
+func dedupID(session *models.Session, questionID primitive.ObjectID, participantID string) string {
+	if !session.SecretBallot {
+		return participantID;
+	}
+	return utils.KeyedHash(utils.BallotHashKey(), session.ID.Hex(), questionID.Hex(), participantID);
+}
+
+// toSecretBallot splits a vote into an anonymous ballot and a participation record. The ballot
+// gets a random id and a coarse timestamp so it cannot be matched back to the participation.
+func toSecretBallot(vote models.Vote, participantID string) (models.Vote, models.Participation) {
+	ballotTime := time.Now().Truncate(utils.BALLOT_TIME_GRANULARITY);
+
+	participation := models.Participation{
+		ID: randomObjectID(ballotTime),
+		SessionID: vote.SessionID,
+		QuestionID: vote.QuestionID,
+		ParticipantID: participantID,
+		CreatedAt: ballotTime,
+	}
+
+	ballot := vote;
+	ballot.ID = randomObjectID(ballotTime);
+	ballot.ParticipantID = primitive.NilObjectID;
+	ballot.CreatedAt = ballotTime;
+	ballot.ProcessedAt = ballotTime;
+
+	return ballot, participation;
+}
+
+// ballotContent is what a secret ballot voter chose. In Kafka events it only travels sealed,
+// so the log never holds a participant id next to a readable selection.
+type ballotContent struct {
+	SelectedOptions []int `json:"selectedOptions"`
+	OtherText string `json:"otherText,omitempty"`
+	Availability []string `json:"availability,omitempty"`
+	Allocation []int `json:"allocation,omitempty"`
+	RowAnswers []int `json:"rowAnswers,omitempty"`
+}
+
+func sealContent(content ballotContent) (string, error) {
+	plaintext, err := json.Marshal(content);
+	if err != nil {
+		return "", err;
+	}
+	return utils.SealWithKey(utils.BallotHashKey(), plaintext);
+}
+
+func openContent(sealed string) (ballotContent, error) {
+	var content ballotContent;
+	plaintext, err := utils.OpenWithKey(utils.BallotHashKey(), sealed);
+	if err != nil {
+		return content, fmt.Errorf("invalid sealed ballot");
+	}
+	if err := json.Unmarshal(plaintext, &content); err != nil {
+		return content, fmt.Errorf("invalid sealed ballot");
+	}
+	return content, nil;
+}
+
+// SealVoteEvent moves the answer of a secret ballot vote event into its sealed ballot.
+func SealVoteEvent(event *VoteSubmittedEvent) error {
+	sealed, err := sealContent(ballotContent{event.SelectedOptions, event.OtherText, event.Availability, event.Allocation, event.RowAnswers});
+	if err != nil {
+		return err;
+	}
+	*event = VoteSubmittedEvent{
+		EventID: event.EventID,
+		Type: event.Type,
+		VoteID: event.VoteID,
+		SessionID: event.SessionID,
+		QuestionID: event.QuestionID,
+		ParticipantID: event.ParticipantID,
+		SealedBallot: sealed,
+		Timestamp: event.Timestamp,
+	};
+	return nil;
+}
+
+// SealSurveyEvent seals every answer of a secret ballot survey submission.
+func SealSurveyEvent(event *SurveySubmittedEvent) error {
+	for i, answer := range event.Answers {
+		sealed, err := sealContent(ballotContent{answer.SelectedOptions, answer.OtherText, answer.Availability, answer.Allocation, answer.RowAnswers});
+		if err != nil {
+			return err;
+		}
+		event.Answers[i] = SurveyAnswerEvent{VoteID: answer.VoteID, QuestionID: answer.QuestionID, SealedBallot: sealed};
+	}
+	return nil;
+}
+
+// openVoteEvent restores the answer of a sealed vote event for processing.
+func openVoteEvent(event *VoteSubmittedEvent) error {
+	if event.SealedBallot == "" {
+		return nil;
+	}
+	content, err := openContent(event.SealedBallot);
+	if err != nil {
+		return err;
+	}
+	event.SelectedOptions, event.OtherText = content.SelectedOptions, content.OtherText;
+	event.Availability, event.Allocation, event.RowAnswers = content.Availability, content.Allocation, content.RowAnswers;
+	event.SealedBallot = "";
+	return nil;
+}
+
+func openSurveyEvent(event *SurveySubmittedEvent) error {
+	for i, answer := range event.Answers {
+		if answer.SealedBallot == "" {
+			continue;
+		}
+		content, err := openContent(answer.SealedBallot);
+		if err != nil {
+			return err;
+		}
+		event.Answers[i] = SurveyAnswerEvent{
+			VoteID: answer.VoteID,
+			QuestionID: answer.QuestionID,
+			SelectedOptions: content.SelectedOptions,
+			OtherText: content.OtherText,
+			Availability: content.Availability,
+			Allocation: content.Allocation,
+			RowAnswers: content.RowAnswers,
+		};
+	}
+	return nil;
+}
+
+// randomObjectID builds an object id without the process counter, which would otherwise
+// reveal the insert order shared by a ballot and its participation.
+func randomObjectID(timestamp time.Time) primitive.ObjectID {
+	var id primitive.ObjectID;
+	binary.BigEndian.PutUint32(id[0:4], uint32(timestamp.Unix()));
+	rand.Read(id[4:]);
+	return id;
+}
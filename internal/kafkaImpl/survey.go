@@ -0,0 +1,158 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// processSurvey stores all answers of a survey submission or none of them, then
+// emits a results update for every answered question.
+func processSurvey(surveyEvent SurveySubmittedEvent) error {
+	sessionID, err := primitive.ObjectIDFromHex(surveyEvent.SessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID")
+	}
+
+	participantObjID, err := primitive.ObjectIDFromHex(surveyEvent.ParticipantID)
+	if err != nil {
+		return fmt.Errorf("invalid participant ID")
+	}
+
+	session, err := repository.GetSessionByID(sessionID);
+	if err != nil {
+		return fmt.Errorf("session not found");
+	}
+	if session.Status != utils.ACTIVE {
+		return fmt.Errorf("session is not active");
+	}
+
+	questions := make([]models.Question, len(surveyEvent.Answers));
+	for i, answer := range surveyEvent.Answers {
+		questionID, err := primitive.ObjectIDFromHex(answer.QuestionID);
+		if err != nil {
+			return fmt.Errorf("invalid question ID");
+		}
+
+		question, found := session.FindQuestion(questionID);
+		if !found {
+			return fmt.Errorf("question not found");
+		}
+		if question.ClosedAt != nil {
+			return fmt.Errorf("question is closed");
+		}
+		if session.IsQuiz() && question.OpenedAt == nil {
+			return fmt.Errorf("question is not open");
+		}
+		questions[i] = question;
+	}
+
+	submitted := make(models.ParticipantAnswers);
+	for i, answer := range surveyEvent.Answers {
+		submitted[questions[i].ID] = answer.SelectedOptions;
+	}
+	if err := checkQuestionsShown(session, questions, surveyEvent.ParticipantID, submitted); err != nil {
+		return err;
+	}
+
+	weight, err := participantWeight(session, surveyEvent.ParticipantID);
+	if err != nil {
+		return err;
+	}
+
+	// lock every answer first, a single duplicate rejects the whole submission. Until the votes
+	// are stored, any way out of here (error or panic) gives the locks and seats back.
+	locked := []primitive.ObjectID{};
+	reserved := []int{};
+	stored := false;
+	defer func() {
+		if stored {
+			return;
+		}
+		for _, i := range reserved {
+			services.ReleaseSeats(sessionID, questions[i].ID, questions[i].OptionCapacities, surveyEvent.Answers[i].SelectedOptions);
+		}
+		for _, questionID := range locked {
+			releaseVoteLock(sessionID, questionID, dedupID(session, questionID, surveyEvent.ParticipantID));
+		}
+	}()
+
+	for _, question := range questions {
+		if err := checkDuplicateVote(sessionID, question.ID, dedupID(session, question.ID, surveyEvent.ParticipantID)); err != nil {
+			log.Printf("Survey %s rejected, question %s already answered: %v", surveyEvent.SubmissionID, question.ID.Hex(), err)
+			return fmt.Errorf("duplicate vote");
+		}
+		locked = append(locked, question.ID);
+	}
+
+	// seats are all or nothing too, surveys are never waitlisted.
+	for i, answer := range surveyEvent.Answers {
+		if _, err := reserveSeats(session, questions[i], answer.SelectedOptions, nil); err != nil {
+			return err;
+		}
+		reserved = append(reserved, i);
+	}
+
+	votes := make([]models.Vote, len(surveyEvent.Answers));
+	receipts := make([]models.VoteReceipt, len(surveyEvent.Answers));
+	totalScore := 0;
+	for i, answer := range surveyEvent.Answers {
+		votes[i] = buildVote(session, questions[i], answer.VoteID, participantObjID, answer.SelectedOptions, answer.OtherText, surveyEvent.Timestamp);
+		votes[i].Weight = weight;
+		votes[i].Availability = answer.Availability;
+		votes[i].Allocation = answer.Allocation;
+		votes[i].RowAnswers = answer.RowAnswers;
+		receipts[i] = sealBallot(&votes[i], answer.VoteID);
+		totalScore += votes[i].Score;
+	}
+
+	if session.SecretBallot {
+		participations := make([]models.Participation, len(votes));
+		for i := range votes {
+			votes[i], participations[i] = toSecretBallot(votes[i], surveyEvent.ParticipantID);
+		}
+		err = repository.SaveSecretBallots(votes, participations);
+	} else {
+		err = repository.SaveVotesToMongo(votes);
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save survey: %v", err);
+	}
+	stored = true;
+
+	for _, voteReceipt := range receipts {
+		deliverReceipt(session, voteReceipt, surveyEvent.ParticipantID);
+	}
+	recordQuestionViews(session, questions, surveyEvent.ParticipantID);
+
+	if session.HasRoster() {
+		if err := services.MarkRosterVoted(sessionID, surveyEvent.ParticipantID); err != nil {
+			log.Printf("Warning: Failed to update roster turnout: %v", err)
+		}
+	}
+
+	if session.IsQuiz() {
+		if err := services.AddQuizScore(sessionID, surveyEvent.ParticipantID, totalScore); err != nil {
+			log.Printf("Warning: Failed to update quiz leaderboard: %v", err)
+		}
+	}
+
+	for _, vote := range votes {
+		if err := UpdateRealTimeResults(vote); err != nil {
+			log.Printf("Warning: Failed to update real-time results for question %s: %v", vote.QuestionID.Hex(), err)
+		}
+	}
+
+	for _, vote := range votes {
+		evaluateClosingRules(sessionID, vote.QuestionID);
+	}
+
+	log.Printf("Successfully processed survey: %s (%d answers)", surveyEvent.SubmissionID, len(votes))
+	return nil;
+}
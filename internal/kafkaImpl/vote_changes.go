@@ -0,0 +1,138 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/repository"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// replaceVote swaps the answer of a participant who already voted on a changeable question.
+func replaceVote(session *models.Session, question models.Question, voteEvent VoteSubmittedEvent) error {
+	participantObjID, err := primitive.ObjectIDFromHex(voteEvent.ParticipantID);
+	if err != nil {
+		return fmt.Errorf("invalid participant ID");
+	}
+
+	held, err := heldOptions(session, question, voteEvent.ParticipantID);
+	if err != nil {
+		return err;
+	}
+	if _, err := reserveSeats(session, question, voteEvent.SelectedOptions, held); err != nil {
+		return err;
+	}
+
+	revision := buildVote(session, question, voteEvent.VoteID, participantObjID, voteEvent.SelectedOptions, voteEvent.OtherText, voteEvent.Timestamp);
+	revision.Availability = voteEvent.Availability;
+	revision.Allocation = voteEvent.Allocation;
+	revision.RowAnswers = voteEvent.RowAnswers;
+	voteReceipt := sealBallot(&revision, voteEvent.VoteID);
+	if err := reviseVote(session, revision, voteEvent.ParticipantID, "change"); err != nil {
+		services.ReleaseSeats(session.ID, question.ID, question.OptionCapacities, withoutOptions(voteEvent.SelectedOptions, held));
+		return err;
+	}
+	releaseSeats(session, question, withoutOptions(held, voteEvent.SelectedOptions));
+
+	// the earlier receipt no longer matches a counted ballot.
+	deliverReceipt(session, voteReceipt, voteEvent.ParticipantID);
+	return nil;
+}
+
+// processRetraction withdraws the answer of a participant on a retractable question. The vote
+// document is kept with an empty selection (and its history) so the participant can vote again later.
+func processRetraction(voteEvent VoteSubmittedEvent) error {
+	sessionID, err := primitive.ObjectIDFromHex(voteEvent.SessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID")
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(voteEvent.QuestionID)
+	if err != nil {
+		return fmt.Errorf("invalid question ID")
+	}
+
+	session, err := repository.GetSessionByID(sessionID);
+	if err != nil {
+		return fmt.Errorf("session not found");
+	}
+
+	question, found := session.FindQuestion(questionID);
+	if !found {
+		return fmt.Errorf("question not found");
+	}
+
+	if question.ClosedAt != nil || session.Status != utils.ACTIVE {
+		return fmt.Errorf("question is closed");
+	}
+
+	if !question.AllowsRetraction() {
+		return fmt.Errorf("vote cannot be retracted");
+	}
+
+	participantObjID, err := primitive.ObjectIDFromHex(voteEvent.ParticipantID)
+	if err != nil {
+		return fmt.Errorf("invalid participant ID")
+	}
+
+	revision := models.Vote{
+		SessionID: sessionID,
+		QuestionID: questionID,
+		ParticipantID: participantObjID,
+		SelectedOptions: []int{},
+		Retracted: true,
+	}
+
+	held, err := heldOptions(session, question, voteEvent.ParticipantID);
+	if err != nil {
+		return err;
+	}
+	if err := reviseVote(session, revision, voteEvent.ParticipantID, "retract"); err != nil {
+		return err;
+	}
+
+	releaseSeats(session, question, held);
+	return nil;
+}
+
+// heldOptions returns the options the participant currently holds a seat on.
+func heldOptions(session *models.Session, question models.Question, participantID string) ([]int, error) {
+	if !question.HasCapacity() {
+		return nil, nil;
+	}
+
+	answers, err := services.GetParticipantAnswers(session.ID, participantID);
+	if err != nil {
+		return nil, err;
+	}
+	return answers[question.ID], nil;
+}
+
+func reviseVote(session *models.Session, revision models.Vote, participantID string, action string) error {
+	if session.Status != utils.ACTIVE {
+		return fmt.Errorf("session is not active");
+	}
+
+	previous, err := repository.ReviseVote(revision, action);
+	if err != nil {
+		return err;
+	}
+
+	if session.IsQuiz() && previous.Score != revision.Score {
+		if err := services.AddQuizScore(session.ID, participantID, revision.Score - previous.Score); err != nil {
+			log.Printf("Warning: Failed to update quiz leaderboard: %v", err)
+		}
+	}
+
+	revision.ID = previous.ID;
+	if err := UpdateRealTimeResults(revision); err != nil {
+		log.Printf("Warning: Failed to update real-time results: %v", err)
+	}
+
+	log.Printf("Vote %s of participant %s: %s", previous.ID.Hex(), participantID, action)
+	return nil;
+}
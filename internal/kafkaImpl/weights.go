@@ -0,0 +1,130 @@
+package kafkaImpl
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/services"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// participantWeight returns the weight a participant votes with. Weighted sessions only
+// accept participants from the roster.
+func participantWeight(session *models.Session, participantID string) (float64, error) {
+	if !session.Weighted {
+		return 1, nil;
+	}
+
+	entry, err := services.GetRosterEntry(session.ID, participantID);
+	if err != nil {
+		return 0, err;
+	}
+	return entry.Weight, nil;
+}
+
+// applyWeightedResults adds weighted totals and the quorum status to the headcount results.
+func applyWeightedResults(session *models.Session, question models.Question, results *models.QuestionResult) error {
+	if session.Weighted {
+		optionWeights, totalWeight, votersWeight, err := getVoteWeightsForQuestion(session, question);
+		if err != nil {
+			return err;
+		}
+
+		for i := range results.Options {
+			weight := optionWeights[results.Options[i].Index];
+			results.Options[i].Weight = weight;
+			if totalWeight > 0 {
+				results.Options[i].WeightedPercentage = (weight / totalWeight) * 100;
+			}
+		}
+		results.TotalWeight = totalWeight;
+		results.VotersWeight = votersWeight;
+	}
+
+	if session.QuorumPercent > 0 {
+		eligibleCount, eligibleWeight, err := services.GetRosterTotals(session.ID);
+		if err != nil {
+			return err;
+		}
+
+		quorum := &models.QuorumStatus{
+			RequiredPercent: session.QuorumPercent,
+			Eligible: float64(eligibleCount),
+			Participating: float64(results.VotersCount),
+		}
+		if session.Weighted {
+			quorum.Eligible = eligibleWeight;
+			quorum.Participating = results.VotersWeight;
+		}
+		quorum.Reached = quorum.Eligible > 0 && quorum.Participating / quorum.Eligible * 100 >= session.QuorumPercent;
+		results.Quorum = quorum;
+	}
+
+	return nil;
+}
+
+// getVoteWeightsForQuestion sums the voter weights per option, over all selections,
+// and over voters (each vote counted once).
+func getVoteWeightsForQuestion(session *models.Session, question models.Question) (map[int]float64, float64, float64, error) {
+	mongo := database.GetMongoInstance()
+	votesCollection := mongo.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background()
+
+	match := bson.M{
+		"session_id":  session.ID,
+		"question_id": question.ID,
+		"retracted":   bson.M{"$ne": true},
+	}
+	weight := bson.M{"$ifNull": []interface{}{"$weight", 1}};
+
+	cursor, err := votesCollection.Aggregate(ctx, []bson.M{
+		{"$match": match},
+		{"$facet": bson.M{
+			"options": []bson.M{
+				{"$unwind": "$selected_options"},
+				{"$group": bson.M{"_id": "$selected_options", "weight": bson.M{"$sum": weight}}},
+			},
+			"voters": []bson.M{
+				{"$group": bson.M{"_id": nil, "weight": bson.M{"$sum": weight}}},
+			},
+		}},
+	});
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("weight aggregation failed: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	var result struct {
+		Options []struct {
+			Option int `bson:"_id"`
+			Weight float64 `bson:"weight"`
+		} `bson:"options"`
+		Voters []struct {
+			Weight float64 `bson:"weight"`
+		} `bson:"voters"`
+	}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to decode weights: %v", err);
+		}
+	}
+
+	optionWeights := make(map[int]float64);
+	totalWeight := 0.0;
+	for _, option := range result.Options {
+		optionWeights[option.Option] = option.Weight;
+		totalWeight += option.Weight;
+	}
+
+	votersWeight := 0.0;
+	if len(result.Voters) > 0 {
+		votersWeight = result.Voters[0].Weight;
+	}
+
+	return optionWeights, totalWeight, votersWeight, nil;
+}
@@ -0,0 +1,71 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// answers of one participant, question ID -> selected options.
+type ParticipantAnswers map[primitive.ObjectID][]int
+
+func (s Session) HasBranching() bool {
+	for _, question := range s.Questions {
+		if len(question.ShowIf) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// FindQuestionByKey returns the question with the given author key.
+func (s *Session) FindQuestionByKey(key string) (Question, bool) {
+	for _, question := range s.Questions {
+		if question.Key != "" && question.Key == key {
+			return question, true
+		}
+	}
+	return Question{}, false
+}
+
+// QuestionVisible reports whether the question is shown to a participant with these answers.
+// A condition on a question that was not answered (yet) does not hold.
+func (s *Session) QuestionVisible(question Question, answers ParticipantAnswers) bool {
+	for _, condition := range question.ShowIf {
+		source, found := s.FindQuestionByKey(condition.QuestionKey)
+		if !found {
+			return false
+		}
+
+		selected, answered := answers[source.ID]
+		if !answered || !selectsAnyOf(selected, condition.AnyOf) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextQuestion returns the first open question, in session order, that is shown to the
+// participant and not answered yet, pulse questions aside. It returns false once there is nothing left to answer.
+func (s *Session) NextQuestion(answers ParticipantAnswers) (Question, bool) {
+	for _, question := range s.Questions {
+		// pulse questions are answered live, they never block the next question.
+		if question.ClosedAt != nil || question.Type == "pulse" {
+			continue
+		}
+		if _, answered := answers[question.ID]; answered {
+			continue
+		}
+		if s.QuestionVisible(question, answers) {
+			return question, true
+		}
+	}
+	return Question{}, false
+}
+
+func selectsAnyOf(selected, anyOf []int) bool {
+	for _, option := range selected {
+		for _, wanted := range anyOf {
+			if option == wanted {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// Locales returns the locales the session can be shown in, the default locale first.
+// Sessions without translations return nothing.
+func (s Session) Locales() []string {
+	seen := map[string]bool{};
+	locales := []string{};
+	add := func(locale string) {
+		if locale != "" && !seen[strings.ToLower(locale)] {
+			seen[strings.ToLower(locale)] = true;
+			locales = append(locales, locale);
+		}
+	}
+
+	translated := []string{};
+	for locale := range s.TitleTranslations {
+		translated = append(translated, locale);
+	}
+	for _, question := range s.Questions {
+		for locale := range question.Translations {
+			translated = append(translated, locale);
+		}
+	}
+	if len(translated) == 0 {
+		return nil;
+	}
+	sort.Strings(translated);
+
+	add(s.DefaultLocale);
+	for _, locale := range translated {
+		add(locale);
+	}
+	return locales;
+}
+
+// IsDefaultLocale reports whether texts in this locale are the untranslated ones.
+func (s Session) IsDefaultLocale(locale string) bool {
+	return locale == "" || strings.EqualFold(locale, s.DefaultLocale);
+}
+
+// Localized returns the session with the title and question texts in the given locale.
+// Missing translations fall back to the default texts; translations are not sent along.
+func (s Session) Localized(locale string) Session {
+	view := s;
+	view.TitleTranslations = nil;
+	if !s.IsDefaultLocale(locale) {
+		if title, found := lookupLocale(s.TitleTranslations, locale); found && title != "" {
+			view.Title = title;
+		}
+		view.Locale = locale;
+	} else {
+		view.Locale = s.DefaultLocale;
+	}
+
+	view.Questions = make([]Question, len(s.Questions));
+	for i, question := range s.Questions {
+		view.Questions[i] = question.Localized(locale);
+	}
+	return view;
+}
+
+// Localized returns the question with its text, options and rows in the given locale.
+// Option indexes are unchanged, so votes stay language-independent.
+func (q Question) Localized(locale string) Question {
+	view := q;
+	view.Translations = nil;
+
+	translation, found := lookupLocale(q.Translations, locale);
+	if !found {
+		return view;
+	}
+	if translation.Text != "" {
+		view.Text = translation.Text;
+	}
+	view.Options = localizedTexts(q.Options, translation.Options);
+	view.Rows = localizedTexts(q.Rows, translation.Rows);
+	return view;
+}
+
+// LocalizeResults renders the texts of a question's results in the given locale. Counts are untouched.
+func (q Question) LocalizeResults(results QuestionResult, locale string) QuestionResult {
+	translation, found := lookupLocale(q.Translations, locale);
+	if !found {
+		return results;
+	}
+
+	if translation.Text != "" {
+		results.Text = translation.Text;
+	}
+	results.Options = localizedCounts(results.Options, translation.Options);
+	if len(results.Rows) > 0 {
+		rows := make([]RowResult, len(results.Rows));
+		for i, row := range results.Rows {
+			if row.Index >= 0 && row.Index < len(translation.Rows) && translation.Rows[row.Index] != "" {
+				row.Text = translation.Rows[row.Index];
+			}
+			row.Options = localizedCounts(row.Options, translation.Options);
+			rows[i] = row;
+		}
+		results.Rows = rows;
+	}
+	return results;
+}
+
+func localizedCounts(counts []OptionCount, texts []string) []OptionCount {
+	if len(texts) == 0 {
+		return counts;
+	}
+	localized := make([]OptionCount, len(counts));
+	for i, count := range counts {
+		if count.Index >= 0 && count.Index < len(texts) && texts[count.Index] != "" {
+			count.Text = texts[count.Index];
+		}
+		localized[i] = count;
+	}
+	return localized;
+}
+
+// localizedTexts replaces each text that has a non-empty translation.
+func localizedTexts(texts []string, translations []string) []string {
+	if len(translations) == 0 {
+		return texts;
+	}
+	localized := make([]string, len(texts));
+	for i, text := range texts {
+		if i < len(translations) && translations[i] != "" {
+			text = translations[i];
+		}
+		localized[i] = text;
+	}
+	return localized;
+}
+
+// locale tags are compared case-insensitively, "pt-BR" and "pt-br" are the same locale.
+func lookupLocale[T any](translations map[string]T, locale string) (T, bool) {
+	var zero T;
+	if locale == "" {
+		return zero, false;
+	}
+	if value, found := translations[locale]; found {
+		return value, true;
+	}
+	for key, value := range translations {
+		if strings.EqualFold(key, locale) {
+			return value, true;
+		}
+	}
+	return zero, false;
+}
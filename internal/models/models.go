@@ -20,6 +20,18 @@ type Session struct {
 	JoinCode string `bson:"join_code" json:"joinCode"`
 	Title string `bson:"title" json:"title"`
 	Status string `bson:"status" json:"status"`//active,closed,drafted
+	Mode string `bson:"mode,omitempty" json:"mode,omitempty"`//poll (default) or quiz
+	QnAModeration bool `bson:"qna_moderation,omitempty" json:"qnaModeration,omitempty"`// audience questions wait for approval
+	Weighted bool `bson:"weighted,omitempty" json:"weighted,omitempty"`// votes count with the voter's roster weight
+	QuorumPercent float64 `bson:"quorum_percent,omitempty" json:"quorumPercent,omitempty"`// share of eligible weight (or headcount) that must vote
+	InviteOnly bool `bson:"invite_only,omitempty" json:"inviteOnly,omitempty"`// only roster voters with an invite token may vote
+	SecretBallot bool `bson:"secret_ballot,omitempty" json:"secretBallot,omitempty"`// who voted and what was chosen are stored unlinked
+	ClosingRules *ClosingRules `bson:"closing_rules,omitempty" json:"closingRules,omitempty"`
+	Privacy *PrivacySettings `bson:"privacy,omitempty" json:"privacy,omitempty"`
+	ShuffleQuestions bool `bson:"shuffle_questions,omitempty" json:"shuffleQuestions,omitempty"`// question order per participant
+	DefaultLocale string `bson:"default_locale,omitempty" json:"defaultLocale,omitempty"`// language of Title and the question texts, e.g. "en"
+	TitleTranslations map[string]string `bson:"title_translations,omitempty" json:"titleTranslations,omitempty"`// locale -> title
+	Locale string `bson:"-" json:"locale,omitempty"`// participant views only: locale the texts are rendered in
 	Questions []Question `bson:"questions" json:"questions"`
 	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"` 
@@ -27,20 +39,120 @@ type Session struct {
 
 type Question struct {
 	ID primitive.ObjectID `bson:"id,omitempty" json:"id"`
+	Key string `bson:"key,omitempty" json:"key,omitempty"`// author-chosen reference used by ShowIf
 	Text string `bson:"text" json:"text"`
 	Options []string `bson:"options" json:"options"`
-	Type string `bson:"type" json:"type"`//single or multiple
+	OptionImages []OptionImage `bson:"option_images,omitempty" json:"optionImages,omitempty"`// one per option, empty id for none
+	Type string `bson:"type" json:"type"`//single, multiple, schedule, allocation, matrix or pulse
+	Rows []string `bson:"rows,omitempty" json:"rows,omitempty"`// matrix questions: items rated on the options (columns)
+	SliderMin int `bson:"slider_min,omitempty" json:"sliderMin,omitempty"`// pulse questions, 0 to 100 by default
+	SliderMax int `bson:"slider_max,omitempty" json:"sliderMax,omitempty"`
+	Slots []TimeSlot `bson:"slots,omitempty" json:"slots,omitempty"`// schedule questions: one slot per option
+	Budget int `bson:"budget,omitempty" json:"budget,omitempty"`// allocation questions: points each participant distributes
+	BudgetUnit string `bson:"budget_unit,omitempty" json:"budgetUnit,omitempty"`// e.g. "points" or "EUR", display only
+	AllocationCaps []int `bson:"allocation_caps,omitempty" json:"allocationCaps,omitempty"`// most points one participant may give an option, 0 is no cap
+	Required bool `bson:"required,omitempty" json:"required,omitempty"`// must be answered in survey submissions
+	VotePolicy string `bson:"vote_policy,omitempty" json:"votePolicy,omitempty"`// locked (default), changeable or retractable
+	ResultsVisibility string `bson:"results_visibility,omitempty" json:"resultsVisibility,omitempty"`// always (default), after_vote, after_close or organizer_only
+
+	// quiz mode only. CorrectOptions are never sent to participants, see ParticipantView.
+	CorrectOptions []int `bson:"correct_options,omitempty" json:"correctOptions,omitempty"`
+	Points int `bson:"points,omitempty" json:"points,omitempty"`
+	TimeLimitSeconds int `bson:"time_limit_seconds,omitempty" json:"timeLimitSeconds,omitempty"`
+	OpenedAt *time.Time `bson:"opened_at,omitempty" json:"openedAt,omitempty"`
+	ClosedAt *time.Time `bson:"closed_at,omitempty" json:"closedAt,omitempty"`
+	ClosingRules *ClosingRules `bson:"closing_rules,omitempty" json:"closingRules,omitempty"`
+	ShowIf []DisplayCondition `bson:"show_if,omitempty" json:"showIf,omitempty"`// all must hold for the question to be shown
+	FreeTextOptions []int `bson:"free_text_options,omitempty" json:"freeTextOptions,omitempty"`// "Other (please specify)" options taking otherText
+	ShuffleOptions bool `bson:"shuffle_options,omitempty" json:"shuffleOptions,omitempty"`// per participant, stable across reconnects
+	PinLastOption bool `bson:"pin_last_option,omitempty" json:"pinLastOption,omitempty"`// keeps e.g. "None of the above" last when shuffling
+	OptionOrder []int `bson:"-" json:"optionOrder,omitempty"`// participant views only: canonical index of each displayed option
+	OptionCapacities []int `bson:"option_capacities,omitempty" json:"optionCapacities,omitempty"`// seats per option, 0 is unlimited
+	Waitlist bool `bson:"waitlist,omitempty" json:"waitlist,omitempty"`// queue votes for full options instead of rejecting them
+	Translations map[string]QuestionTranslation `bson:"translations,omitempty" json:"translations,omitempty"`// locale -> texts, same option and row order
+}
+
+// texts of a question in another language. Options and Rows line up with the question's by index.
+type QuestionTranslation struct {
+	Text string `bson:"text" json:"text"`
+	Options []string `bson:"options,omitempty" json:"options,omitempty"`
+	Rows []string `bson:"rows,omitempty" json:"rows,omitempty"`
+}
+
+// image shown with an option. ID comes from the image upload, the URLs are resolved when sent out.
+type OptionImage struct {
+	ID string `bson:"id,omitempty" json:"id,omitempty"`
+	URL string `bson:"-" json:"url,omitempty"`
+	ThumbnailURL string `bson:"-" json:"thumbnailUrl,omitempty"`
+}
+
+// time slot of a schedule question. Timezone is the IANA zone the slot is shown in.
+type TimeSlot struct {
+	Start time.Time `bson:"start" json:"start"`
+	End time.Time `bson:"end" json:"end"`
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// shows a question only when the participant picked one of AnyOf on the question with QuestionKey.
+type DisplayCondition struct {
+	QuestionKey string `bson:"question_key" json:"questionKey"`
+	AnyOf []int `bson:"any_of" json:"anyOf"`
+}
+
+// conditions that close a session or question automatically, any one of them is enough.
+type ClosingRules struct {
+	OnQuorum bool `bson:"on_quorum,omitempty" json:"onQuorum,omitempty"`// once the session quorumPercent is reached
+	AllRosterVoted bool `bson:"all_roster_voted,omitempty" json:"allRosterVoted,omitempty"`
+	MaxVotes int `bson:"max_votes,omitempty" json:"maxVotes,omitempty"`
+	Deadline *time.Time `bson:"deadline,omitempty" json:"deadline,omitempty"`
+}
+
+// protection applied to the results participants see. Organizers of the session keep exact counts.
+type PrivacySettings struct {
+	MinCount int `bson:"min_count,omitempty" json:"minCount,omitempty"`// option counts below k are hidden
+	RoundPercentTo float64 `bson:"round_percent_to,omitempty" json:"roundPercentTo,omitempty"`// e.g. 5 rounds to the nearest 5%
+	Epsilon float64 `bson:"epsilon,omitempty" json:"epsilon,omitempty"`// laplace noise spent per release, 0 disables noise
+	EpsilonBudget float64 `bson:"epsilon_budget,omitempty" json:"epsilonBudget,omitempty"`// total epsilon the session may spend
 }
 
 type Vote struct {
 	ID primitive.ObjectID `bson:"_id" json:"id"`
 	SessionID primitive.ObjectID `bson:"session_id" json:"sessionId"`
 	QuestionID primitive.ObjectID `bson:"question_id" json:"questionId"`
-	ParticipantID primitive.ObjectID `bson:"participant_id" json:"participantId"`
+	ParticipantID primitive.ObjectID `bson:"participant_id,omitempty" json:"participantId"`// not stored for secret ballots
 	SelectedOptions []int `bson:"selected_options" json:"selectedOptions"`
 	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
 	Processed bool `bson:"processed" json:"processed"`
 	ProcessedAt time.Time `bson:"processed_at" json:"processedAt,omitempty"`
+	Correct bool `bson:"correct,omitempty" json:"correct,omitempty"`
+	Score int `bson:"score,omitempty" json:"score,omitempty"`
+	Weight float64 `bson:"weight,omitempty" json:"weight,omitempty"`// voter weight when the vote was cast
+	Retracted bool `bson:"retracted,omitempty" json:"retracted,omitempty"`
+	UpdatedAt *time.Time `bson:"updated_at,omitempty" json:"updatedAt,omitempty"`
+	History []VoteRevision `bson:"history,omitempty" json:"history,omitempty"`
+	BallotHash string `bson:"ballot_hash,omitempty" json:"ballotHash,omitempty"`// leaf of the tally merkle tree
+	OtherText string `bson:"other_text,omitempty" json:"otherText,omitempty"`// free text of a selected free-text option
+	OtherTextNormalized string `bson:"other_text_normalized,omitempty" json:"-"`
+	Availability []string `bson:"availability,omitempty" json:"availability,omitempty"`// schedule questions: yes, if_need_be or no per slot
+	Allocation []int `bson:"allocation,omitempty" json:"allocation,omitempty"`// allocation questions: points per option
+	RowAnswers []int `bson:"row_answers,omitempty" json:"rowAnswers,omitempty"`// matrix questions: column picked per row
+}
+
+// record that a participant voted on a question of a secret ballot session. It shares
+// no key with the ballot (the vote document), so the two cannot be joined.
+type Participation struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	SessionID primitive.ObjectID `bson:"session_id" json:"sessionId"`
+	QuestionID primitive.ObjectID `bson:"question_id" json:"questionId"`
+	ParticipantID string `bson:"participant_id" json:"participantId"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// previous selection of a vote that was changed or retracted.
+type VoteRevision struct {
+	SelectedOptions []int `bson:"selected_options" json:"selectedOptions"`
+	Action string `bson:"action" json:"action"`// change or retract
+	ReplacedAt time.Time `bson:"replaced_at" json:"replacedAt"`
 }
 
 
@@ -52,6 +164,35 @@ type QuestionResult struct {
 	Options []OptionCount `json:"options"`
 	TotalVotes int `json:"totalVotes"`
 	VotersCount int `json:"votersCount"`
+	TotalWeight float64 `json:"totalWeight,omitempty"` // weighted sessions only
+	VotersWeight float64 `json:"votersWeight,omitempty"`
+	Quorum *QuorumStatus `json:"quorum,omitempty"`
+	SeenCount int `json:"seenCount,omitempty"` // branching sessions: participants the question was shown to
+	Noisy bool `json:"noisy,omitempty"` // counts carry differential privacy noise
+	Stale bool `json:"stale,omitempty"` // privacy budget spent, last noisy release repeated
+	Ranking []int `json:"ranking,omitempty"` // schedule questions: slot indexes, most available first
+	TotalPoints int `json:"totalPoints,omitempty"` // allocation questions
+	Rows []RowResult `json:"rows,omitempty"` // matrix questions: Options then hold the answers of all rows together
+}
+
+// aggregate of the live slider values of a pulse question at one point in time.
+type PulseSample struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	SessionID primitive.ObjectID `bson:"session_id" json:"sessionId"`
+	QuestionID primitive.ObjectID `bson:"question_id" json:"questionId"`
+	At time.Time `bson:"at" json:"at"`
+	Count int `bson:"count" json:"count"`// participants with a current value
+	Mean float64 `bson:"mean" json:"mean"`
+	Distribution []int `bson:"distribution" json:"distribution"`// participants per equal-width bucket of the slider range
+}
+
+// distribution of the answers to one row of a matrix question.
+type RowResult struct {
+	Index int `json:"index"`
+	Text string `json:"text"`
+	Options []OptionCount `json:"options"`
+	Answered int `json:"answered"`
+	Mean float64 `json:"mean"` // average column position (1-based), for ordinal scales
 }
 
 type OptionCount struct {
@@ -59,6 +200,34 @@ type OptionCount struct {
 	Text string `json:"text"`
 	Count int `json:"count"`
 	Percentage float64 `json:"percentage"`
+	Weight float64 `json:"weight,omitempty"` // weighted sessions only
+	WeightedPercentage float64 `json:"weightedPercentage,omitempty"`
+	Suppressed bool `json:"suppressed,omitempty"` // count hidden, below the session's minimum
+	ImageURL string `json:"imageUrl,omitempty"` // image options only
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+	Capacity int `json:"capacity,omitempty"` // capacity-limited options only
+	Remaining *int `json:"remaining,omitempty"`
+	Waitlisted int `json:"waitlisted,omitempty"`
+	IfNeedBe int `json:"ifNeedBe,omitempty"` // schedule questions: part of Count answering if_need_be
+	Available []SlotParticipant `json:"available,omitempty"` // schedule questions of identified sessions
+	Points int `json:"points,omitempty"` // allocation questions: Count is the number of supporters
+	MeanPoints float64 `json:"meanPoints,omitempty"` // over every participant who answered
+	PointsPercentage float64 `json:"pointsPercentage,omitempty"`
+}
+
+// participant available on a time slot, named from the roster.
+type SlotParticipant struct {
+	ParticipantID string `json:"participantId"`
+	Name string `json:"name,omitempty"`
+	Answer string `json:"answer"` // yes or if_need_be
+}
+
+// quorum of a question, on weight for weighted sessions and on headcount otherwise.
+type QuorumStatus struct {
+	RequiredPercent float64 `json:"requiredPercent"`
+	Eligible float64 `json:"eligible"`
+	Participating float64 `json:"participating"`
+	Reached bool `json:"reached"`
 }
 
 type SessionResults struct {
@@ -78,4 +247,255 @@ type VoteRequest struct {
     QuestionID    string   `json:"questionId"`
     ParticipantID string   `json:"participantId"` // unique id generated from frontend
     SelectedOptions []int  `json:"selectedOptions"`
-}
\ No newline at end of file
+    OtherText     string   `json:"otherText,omitempty"` // when a free-text option is selected
+    Availability  []string `json:"availability,omitempty"` // schedule questions, replaces selectedOptions
+    Allocation    []int    `json:"allocation,omitempty"` // allocation questions, replaces selectedOptions
+    RowAnswers    []int    `json:"rowAnswers,omitempty"` // matrix questions, replaces selectedOptions
+    VoterToken    string   `json:"voterToken,omitempty"` // required for invite-only sessions, from redeeming the invite token
+}
+
+
+// question asked by the audience to the speaker (Q&A), not to be confused with poll questions.
+type AudienceQuestion struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	SessionID primitive.ObjectID `bson:"session_id" json:"sessionId"`
+	ParticipantID string `bson:"participant_id" json:"participantId,omitempty"`
+	AuthorName string `bson:"author_name,omitempty" json:"authorName,omitempty"`
+	Text string `bson:"text" json:"text"`
+	Status string `bson:"status" json:"status"`//pending,approved,hidden,answered
+	Pinned bool `bson:"pinned" json:"pinned"`
+	Upvotes int `bson:"upvotes" json:"upvotes"`
+	UpvoterIDs []string `bson:"upvoter_ids" json:"-"`
+	Score float64 `bson:"-" json:"score"` // hot score, computed when ranking
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+	AnsweredAt *time.Time `bson:"answered_at,omitempty" json:"answeredAt,omitempty"`
+}
+
+// eligible voter of a session. The entry id is used as the participant id when voting.
+type RosterEntry struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	SessionID primitive.ObjectID `bson:"session_id" json:"sessionId"`
+	Email string `bson:"email,omitempty" json:"email,omitempty"`
+	ExternalID string `bson:"external_id,omitempty" json:"externalId,omitempty"`
+	Name string `bson:"name,omitempty" json:"name,omitempty"`
+	Weight float64 `bson:"weight" json:"weight"`// shares, delegate seats... 1 when not weighted
+	InviteTokenHash string `bson:"invite_token_hash,omitempty" json:"-"`// sha256 of the invite token, the token itself is only shown once
+	InviteUsedAt *time.Time `bson:"invite_used_at,omitempty" json:"inviteUsedAt,omitempty"`// invite tokens work once
+	VoterTokenHash string `bson:"voter_token_hash,omitempty" json:"-"`// sha256 of the voter token the invite was redeemed for
+	VotedAt *time.Time `bson:"voted_at,omitempty" json:"votedAt,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// saved layout of a session: its settings and questions, without votes, ids or join code.
+type SessionTemplate struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizerId primitive.ObjectID `bson:"organizer_id" json:"organizerId"`
+	Name string `bson:"name" json:"name"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+	Session Session `bson:"session" json:"session"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// request body to save a template, from an existing session (SessionID) or a session layout.
+type TemplateRequest struct {
+	Name string `json:"name"`
+	Description string `json:"description"`
+	SessionID string `json:"sessionId"`
+	Session *Session `json:"session"`
+}
+
+// reusable question of an organizer's question bank.
+type BankQuestion struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizerId primitive.ObjectID `bson:"organizer_id" json:"organizerId"`
+	Tags []string `bson:"tags" json:"tags"`// lower case
+	Question Question `bson:"question" json:"question"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// request body to create a session from bank questions. The session settings come along as in
+// a new poll, the picked questions are appended to its own questions in the given order.
+type BankSessionRequest struct {
+	Session
+	BankQuestionIDs []string `json:"bankQuestionIds"`
+}
+
+// request body to ask the speaker a question.
+type AudienceQuestionRequest struct {
+	ParticipantID string `json:"participantId"`
+	AuthorName string `json:"authorName"`
+	Text string `json:"text"`
+}
+
+// request body to upvote an audience question.
+type UpvoteRequest struct {
+	ParticipantID string `json:"participantId"`
+}
+
+// quiz leaderboard, built from the redis sorted set of scores.
+type LeaderboardEntry struct {
+	Rank int `json:"rank"`
+	ParticipantID string `json:"participantId"`
+	Score int `json:"score"`
+}
+
+type Leaderboard struct {
+	SessionID string `json:"sessionId"`
+	QuestionID string `json:"questionId,omitempty"` // question that was just closed, empty for final standings
+	Entries []LeaderboardEntry `json:"entries"`
+	TotalParticipants int `json:"totalParticipants"`
+}
+
+
+// ParticipantView returns a copy of the session that is safe to hand out to participants.
+func (s Session) ParticipantView() Session {
+	view := s
+	view.Questions = make([]Question, len(s.Questions))
+	for i, q := range s.Questions {
+		view.Questions[i] = q.ParticipantView()
+	}
+	return view
+}
+
+// ParticipantView strips answer metadata from the question.
+func (q Question) ParticipantView() Question {
+	view := q
+	view.CorrectOptions = nil
+	return view
+}
+
+// FindQuestion looks up a question of the session by its id.
+func (s *Session) FindQuestion(questionID primitive.ObjectID) (Question, bool) {
+	for _, q := range s.Questions {
+		if q.ID == questionID {
+			return q, true
+		}
+	}
+	return Question{}, false
+}
+
+// AllowsVoteChange reports whether a participant may replace their answer while the question is open.
+func (q Question) AllowsVoteChange() bool {
+	return q.VotePolicy == "changeable" || q.VotePolicy == "retractable"
+}
+
+// AllowsRetraction reports whether a participant may withdraw their answer while the question is open.
+func (q Question) AllowsRetraction() bool {
+	return q.VotePolicy == "retractable"
+}
+
+// HasCapacity reports whether any option of the question has a limited number of seats.
+func (q Question) HasCapacity() bool {
+	for _, capacity := range q.OptionCapacities {
+		if capacity > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIdentified reports whether participants are known by name, so results may show who answered what.
+func (s Session) IsIdentified() bool {
+	return s.HasRoster() && !s.IsAnonymous()
+}
+
+// IsAnonymous reports whether answers must not be traced back to participants: secret ballots
+// and sessions with result privacy.
+func (s Session) IsAnonymous() bool {
+	return s.SecretBallot || s.HasPrivacy()
+}
+
+// HasRoster reports whether votes of this session are tied to roster entries.
+func (s Session) HasRoster() bool {
+	return s.InviteOnly || s.Weighted || s.QuorumPercent > 0
+}
+
+// IsQuiz reports whether answers in this session are scored.
+func (s Session) IsQuiz() bool {
+	return s.Mode == "quiz"
+}
+
+func (s Session) HasPrivacy() bool {
+	return s.Privacy != nil && (s.Privacy.MinCount > 0 || s.Privacy.RoundPercentTo > 0 || s.Privacy.Epsilon > 0)
+}
+
+
+// request body to submit answers to many questions of a session at once (survey mode).
+type SurveyRequest struct {
+	SessionID string `json:"sessionId"`
+	ParticipantID string `json:"participantId"`
+	Answers []SurveyAnswer `json:"answers"`
+	VoterToken string `json:"voterToken,omitempty"`
+}
+
+type SurveyAnswer struct {
+	QuestionID string `json:"questionId"`
+	SelectedOptions []int `json:"selectedOptions"`
+	OtherText string `json:"otherText,omitempty"`
+	Availability []string `json:"availability,omitempty"`
+	Allocation []int `json:"allocation,omitempty"`
+	RowAnswers []int `json:"rowAnswers,omitempty"`
+}
+
+// request body to withdraw a vote.
+type RetractVoteRequest struct {
+	SessionID string `json:"sessionId"`
+	QuestionID string `json:"questionId"`
+	ParticipantID string `json:"participantId"`
+	VoterToken string `json:"voterToken,omitempty"`
+}
+
+// roster entry with its invite token, returned once when tokens are issued.
+type RosterInvite struct {
+	EntryID string `json:"entryId"`
+	Email string `json:"email,omitempty"`
+	ExternalID string `json:"externalId,omitempty"`
+	InviteToken string `json:"inviteToken"`
+}
+
+// request body to redeem an invite token for the voter token votes are cast with.
+type RedeemInviteRequest struct {
+	InviteToken string `json:"inviteToken"`
+}
+
+// turnout of one roster entry.
+type RosterTurnout struct {
+	RosterEntry
+	Voted bool `json:"voted"`
+}
+
+// signed proof handed to the participant that their ballot was recorded. BallotHash is
+// sha256(sessionId|questionId|sorted options|nonce), only the participant keeps the nonce.
+type VoteReceipt struct {
+	VoteID string `json:"voteId"`
+	SessionID string `json:"sessionId"`
+	QuestionID string `json:"questionId"`
+	SelectedOptions []int `json:"selectedOptions,omitempty"` // left out of secret ballot receipts
+	RowAnswers []int `json:"rowAnswers,omitempty"` // matrix questions, in row order
+	Allocation []int `json:"allocation,omitempty"` // allocation questions, points per option
+	Availability []string `json:"availability,omitempty"` // schedule questions, answer per slot
+	Nonce string `json:"nonce"`
+	BallotHash string `json:"ballotHash"`
+	IssuedAt time.Time `json:"issuedAt"`
+	Signature string `json:"signature"`
+}
+
+// merkle root over the ballot hashes of one question, published when the session closes.
+type TallyAudit struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	SessionID primitive.ObjectID `bson:"session_id" json:"sessionId"`
+	QuestionID primitive.ObjectID `bson:"question_id" json:"questionId"`
+	MerkleRoot string `bson:"merkle_root" json:"merkleRoot"`
+	LeafCount int `bson:"leaf_count" json:"leafCount"`
+	PublishedAt time.Time `bson:"published_at" json:"publishedAt"`
+	Signature string `bson:"signature" json:"signature"`
+}
+
+// "other" answers of a question sharing one normalized text.
+type OtherTextGroup struct {
+	Normalized string `bson:"_id" json:"normalized"`
+	Count int `bson:"count" json:"count"`
+	Variants []string `bson:"variants" json:"variants"`
+}
@@ -1,9 +1,12 @@
 package realtime
 
 import (
+    "RealTimePoll/internal/utils"
+    "sort"
     "sync"
 	"net/http"
     "github.com/gorilla/websocket"
+    "golang.org/x/time/rate"
 )
 
 
@@ -20,14 +23,27 @@ type Hub struct {
 	register chan *Client;
 	unregister chan *Client;
 	broadcast chan *BroadcastMessage;
+	pulseStore PulseStore;
+	pulseScales pulseScales;
+	verifyParticipant ParticipantVerifier;
 	mutex sync.RWMutex;
 }
 
+// ParticipantVerifier returns the participant id a connection's voter token or participant token
+// proves, or an error when the token does not belong to the claimed participant.
+type ParticipantVerifier func(sessionID, participantID, voterToken, participantToken string) (string, error)
+
 type SessionHub struct {
 	clients map[*Client]bool;
-	broadcast chan []byte;
-	register chan *Client;
-	unregister chan *Client;
+	broadcast chan *BroadcastMessage;
+	stop chan struct{}; // closed when the last client left, ends the run loop and its tickers
+	reactions chan string;
+	reactionCounts map[string]int; // owned by the run loop, flushed every second
+	pulses chan pulseUpdate;
+	pulseValues map[string]map[string]pulseValue; // question -> participant -> latest value, owned by the run loop
+	pulseRanges map[string]PulseScale;
+	pulseStore PulseStore;
+	sessionID string;
 	mutex sync.RWMutex;
 }
 
@@ -38,12 +54,67 @@ type Client struct {
 	sessionID string;
 	userType string; // "organizer" or "participant"
 	userID string;
+	verified bool; // participant id proven by a voter or participant token, only then per-user messages are delivered
+	organizerID string; // from the JWT, organizer connections only
+	reactionLimiter *rate.Limiter;
+	pulseLimiter *rate.Limiter;
+	votedQuestions map[string]bool; // questions this participant is known to have voted on, guarded by the session hub mutex
+	locale string; // ?locale= of the connection, wins over acceptLanguage
+	acceptLanguage string;
 }
 
 
 type BroadcastMessage struct {
 	SessionID string;
 	Data []byte;
+	UserType string; // optional, only clients of this type receive the message
+	UserID string; // optional, only this user's connections receive the message
+	OrganizerID string; // optional, the session's organizer, who gets OrganizerData (when set) instead of Data
+	OrganizerData []byte;
+	QuestionID string; // results messages only, with the question's results visibility
+	Visibility string;
+	Localized map[string][]byte; // optional, Data rendered per session locale, picked by the client's language
+}
+
+// accepts reports whether the message targets the given client.
+func (m *BroadcastMessage) accepts(client *Client) bool {
+	if m.UserType != "" && client.userType != m.UserType {
+		return false;
+	}
+	if m.UserID != "" && (!client.verified || client.userID != m.UserID) {
+		return false;
+	}
+
+	switch m.Visibility {
+	case utils.RESULTS_ORGANIZER_ONLY, utils.RESULTS_AFTER_CLOSE:
+		// after close the results reach participants with the closing message.
+		return m.isSessionOrganizer(client);
+	case utils.RESULTS_AFTER_VOTE:
+		return m.isSessionOrganizer(client) || (client.verified && client.votedQuestions[m.QuestionID]);
+	}
+	return true;
+}
+
+func (m *BroadcastMessage) isSessionOrganizer(client *Client) bool {
+	return m.OrganizerID != "" && client.userType == "organizer" && client.organizerID == m.OrganizerID;
+}
+
+// payloadFor picks the variant of the message the given client receives.
+func (m *BroadcastMessage) payloadFor(client *Client) []byte {
+	if m.OrganizerData != nil && m.isSessionOrganizer(client) {
+		return m.OrganizerData;
+	}
+	if len(m.Localized) > 0 {
+		locales := make([]string, 0, len(m.Localized));
+		for locale := range m.Localized {
+			locales = append(locales, locale);
+		}
+		sort.Strings(locales);
+		if locale := utils.NegotiateLocale(locales, client.locale, client.acceptLanguage); locale != "" {
+			return m.Localized[locale];
+		}
+	}
+	return m.Data;
 }
 
 func NewHub() *Hub {
@@ -52,5 +123,12 @@ func NewHub() *Hub {
 		register: make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast: make(chan *BroadcastMessage),
+		pulseScales: pulseScales{entries: make(map[string]pulseScaleEntry)},
 	}
-}
\ No newline at end of file
+}
+
+func (h *Hub) SetParticipantVerifier(verify ParticipantVerifier) {
+	h.mutex.Lock();
+	defer h.mutex.Unlock();
+	h.verifyParticipant = verify;
+}
@@ -16,7 +16,7 @@ func (h *Hub) Run() {
 			h.unregisterClient(client)
 
 		case message := <-h.broadcast:
-			h.broadcastToSession(message.SessionID, message.Data);
+			h.broadcastToSession(message);
 		}
 	}
 }
@@ -31,9 +31,15 @@ func (h *Hub) registerClient(client *Client) {
 	if !exists {
 		sessionHub = &SessionHub{
 			clients: make(map[*Client]bool),
-			broadcast: make(chan []byte, 256),
-			register: make(chan *Client),
-			unregister: make(chan *Client),
+			broadcast: make(chan *BroadcastMessage, 256),
+			stop: make(chan struct{}),
+			reactions: make(chan string, 256),
+			reactionCounts: make(map[string]int),
+			pulses: make(chan pulseUpdate, 256),
+			pulseValues: make(map[string]map[string]pulseValue),
+			pulseRanges: make(map[string]PulseScale),
+			pulseStore: h.pulseStore,
+			sessionID: client.sessionID,
 		}
 
 		h.sessions[client.sessionID] = sessionHub;
@@ -41,7 +47,7 @@ func (h *Hub) registerClient(client *Client) {
 	}
 
 	// Register client with session hub
-    sessionHub.register <- client
+    sessionHub.addClient(client)
 
     log.Printf("Client registered: session=%s, type=%s, total_clients=%d", 
         client.sessionID, client.userType, len(sessionHub.clients))
@@ -53,10 +59,9 @@ func (h *Hub) unregisterClient(client *Client) {
 	defer h.mutex.Unlock();
 
 	if sessionHub, exists := h.sessions[client.sessionID]; exists {
-		sessionHub.unregister <- client;
-
-		if len(sessionHub.clients) == 0 {
+		if sessionHub.removeClient(client) == 0 {
 			delete(h.sessions, client.sessionID);
+			close(sessionHub.stop);
 			log.Printf("Session hub cleaned up: %s", client.sessionID);
 		}
 	}
@@ -66,11 +71,11 @@ func (h *Hub) unregisterClient(client *Client) {
 	log.Printf("Client unregistered: session=%s", client.sessionID);
 }
 
-func (h *Hub) broadcastToSession(sessionID string, message []byte) {
+func (h *Hub) broadcastToSession(message *BroadcastMessage) {
     h.mutex.RLock()
     defer h.mutex.RUnlock()
 
-    if sessionHub, exists := h.sessions[sessionID]; exists {
+    if sessionHub, exists := h.sessions[message.SessionID]; exists {
         sessionHub.broadcast <- message
     }
 }
\ No newline at end of file
@@ -0,0 +1,217 @@
+package realtime
+
+import (
+	"RealTimePoll/internal/models"
+
+	"encoding/json"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/time/rate"
+)
+
+var (
+	pulseSampleInterval = 5 * time.Second
+	pulseValueTTL = 10 * time.Minute // values not moved for that long stop counting
+	pulseScaleTTL = 10 * time.Second // how long an open pulse question's range is trusted
+	pulseBuckets = 5
+)
+
+// PulseStore gives the hub the slider range of open pulse questions and keeps the sampled time series.
+// It is wired by the consumers so this package does not depend on storage.
+type PulseStore interface {
+	PulseScale(sessionID, questionID string) (PulseScale, bool) // false when not an open pulse question
+	SavePulseSample(sample models.PulseSample)
+}
+
+type PulseScale struct {
+	Min float64
+	Max float64
+}
+
+type pulseUpdate struct {
+	questionID string;
+	userID string;
+	value float64;
+	scale PulseScale;
+}
+
+type pulseValue struct {
+	value float64;
+	at time.Time;
+}
+
+type pulseScaleEntry struct {
+	scale PulseScale;
+	ok bool;
+	loadedAt time.Time;
+}
+
+// pulseScales caches PulseStore lookups so slider moves do not hit storage every time.
+type pulseScales struct {
+	entries map[string]pulseScaleEntry;
+	mutex sync.Mutex;
+}
+
+// newPulseLimiter allows a client 4 slider updates per second, clients are expected to throttle below that.
+func newPulseLimiter() *rate.Limiter {
+	return rate.NewLimiter(4, 4);
+}
+
+func (h *Hub) SetPulseStore(store PulseStore) {
+	h.mutex.Lock();
+	defer h.mutex.Unlock();
+	h.pulseStore = store;
+}
+
+// handlePulse validates a participant's slider value before handing it to the session hub.
+func (c *Client) handlePulse(msg map[string]interface{}) {
+	if c.userType != "participant" {
+		c.sendError("Only participants can send pulse values");
+		return;
+	}
+
+	questionID, _ := msg["questionId"].(string);
+	value, ok := msg["value"].(float64);
+	if questionID == "" || !ok {
+		c.sendError("Pulse needs questionId and a numeric value");
+		return;
+	}
+
+	if !c.pulseLimiter.Allow() {
+		c.sendError("Too many pulse updates, slow down");
+		return;
+	}
+
+	scale, ok := c.hub.pulseScale(c.sessionID, questionID);
+	if !ok {
+		c.sendError("Not an open pulse question");
+		return;
+	}
+	if value < scale.Min || value > scale.Max {
+		c.sendError("Pulse value out of range");
+		return;
+	}
+
+	c.hub.addPulse(c.sessionID, pulseUpdate{questionID: questionID, userID: c.userID, value: value, scale: scale});
+}
+
+func (h *Hub) pulseScale(sessionID, questionID string) (PulseScale, bool) {
+	h.mutex.RLock();
+	store := h.pulseStore;
+	h.mutex.RUnlock();
+	if store == nil {
+		return PulseScale{}, false;
+	}
+
+	key := sessionID + ":" + questionID;
+	h.pulseScales.mutex.Lock();
+	entry, cached := h.pulseScales.entries[key];
+	h.pulseScales.mutex.Unlock();
+	if cached && time.Since(entry.loadedAt) < pulseScaleTTL {
+		return entry.scale, entry.ok;
+	}
+
+	scale, ok := store.PulseScale(sessionID, questionID);
+	h.pulseScales.mutex.Lock();
+	h.pulseScales.entries[key] = pulseScaleEntry{scale: scale, ok: ok, loadedAt: time.Now()};
+	h.pulseScales.mutex.Unlock();
+	return scale, ok;
+}
+
+func (h *Hub) addPulse(sessionID string, update pulseUpdate) {
+	h.mutex.RLock();
+	defer h.mutex.RUnlock();
+
+	sessionHub, exists := h.sessions[sessionID];
+	if !exists {
+		return;
+	}
+
+	select {
+	case sessionHub.pulses <- update:
+	default:
+		// hub is busy, the participant's next move carries the value anyway.
+	}
+}
+
+func (sh *SessionHub) recordPulse(update pulseUpdate) {
+	values, exists := sh.pulseValues[update.questionID];
+	if !exists {
+		values = make(map[string]pulseValue);
+		sh.pulseValues[update.questionID] = values;
+	}
+	values[update.userID] = pulseValue{value: update.value, at: time.Now()};
+	sh.pulseRanges[update.questionID] = update.scale;
+}
+
+// flushPulses broadcasts the aggregate of every pulse question with current values and stores it as a sample.
+func (sh *SessionHub) flushPulses() {
+	now := time.Now();
+	for questionID, values := range sh.pulseValues {
+		for userID, value := range values {
+			if now.Sub(value.at) > pulseValueTTL {
+				delete(values, userID);
+			}
+		}
+		if len(values) == 0 {
+			delete(sh.pulseValues, questionID);
+			delete(sh.pulseRanges, questionID);
+			continue;
+		}
+
+		scale := sh.pulseRanges[questionID];
+		sample := aggregatePulse(values, scale);
+		sample.At = now;
+
+		frame := map[string]interface{}{
+			"type":         "pulse",
+			"sessionId":    sh.sessionID,
+			"questionId":   questionID,
+			"count":        sample.Count,
+			"mean":         sample.Mean,
+			"distribution": sample.Distribution,
+			"min":          scale.Min,
+			"max":          scale.Max,
+			"timestamp":    now,
+		}
+		frameJSON, err := json.Marshal(frame);
+		if err != nil {
+			log.Printf("Failed to marshal pulse frame: %v", err);
+			continue;
+		}
+		sh.broadcastMessage(&BroadcastMessage{SessionID: sh.sessionID, Data: frameJSON});
+
+		if sh.pulseStore != nil {
+			sample.ID = primitive.NewObjectID();
+			sample.SessionID, _ = primitive.ObjectIDFromHex(sh.sessionID);
+			sample.QuestionID, _ = primitive.ObjectIDFromHex(questionID);
+			go sh.pulseStore.SavePulseSample(sample);
+		}
+	}
+}
+
+func aggregatePulse(values map[string]pulseValue, scale PulseScale) models.PulseSample {
+	sample := models.PulseSample{Distribution: make([]int, pulseBuckets)};
+	total := 0.0;
+	width := (scale.Max - scale.Min) / float64(pulseBuckets);
+
+	for _, value := range values {
+		total += value.value;
+		bucket := int(math.Floor((value.value - scale.Min) / width));
+		if bucket >= pulseBuckets {
+			bucket = pulseBuckets - 1;
+		}
+		if bucket < 0 {
+			bucket = 0;
+		}
+		sample.Distribution[bucket]++;
+	}
+
+	sample.Count = len(values);
+	sample.Mean = total / float64(len(values));
+	return sample;
+}
@@ -0,0 +1,89 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	// reactions a participant may send, keyed by the name used on the wire.
+	allowedReactions = map[string]string{
+		"thumbs_up": "👍",
+		"clap":      "👏",
+		"laugh":     "😂",
+		"heart":     "❤️",
+	}
+
+	reactionFlushInterval = 1 * time.Second
+)
+
+// newReactionLimiter allows a client 5 reactions per second with a small burst.
+func newReactionLimiter() *rate.Limiter {
+	return rate.NewLimiter(5, 10);
+}
+
+// handleReaction validates and rate limits a reaction before handing it to the session hub.
+func (c *Client) handleReaction(msg map[string]interface{}) {
+	reaction, _ := msg["reaction"].(string);
+	if _, ok := allowedReactions[reaction]; !ok {
+		c.sendError("Unknown reaction");
+		return;
+	}
+
+	if !c.reactionLimiter.Allow() {
+		c.sendError("Too many reactions, slow down");
+		return;
+	}
+
+	c.hub.addReaction(c.sessionID, reaction);
+}
+
+func (h *Hub) addReaction(sessionID, reaction string) {
+	h.mutex.RLock();
+	defer h.mutex.RUnlock();
+
+	sessionHub, exists := h.sessions[sessionID];
+	if !exists {
+		return;
+	}
+
+	select {
+	case sessionHub.reactions <- reaction:
+	default:
+		// hub is busy, dropping a reaction is fine.
+	}
+}
+
+// flushReactions broadcasts the reactions counted since the last flush as one batched frame.
+func (sh *SessionHub) flushReactions() {
+	if len(sh.reactionCounts) == 0 {
+		return;
+	}
+
+	total := 0;
+	for _, count := range sh.reactionCounts {
+		total += count;
+	}
+
+	frame := map[string]interface{}{
+		"type":      "reactions",
+		"sessionId": sh.sessionID,
+		"counts":    sh.reactionCounts,
+		"emojis":    allowedReactions,
+		"total":     total,
+		"windowMs":  reactionFlushInterval.Milliseconds(),
+		"timestamp": time.Now(),
+	}
+	sh.reactionCounts = make(map[string]int);
+
+	frameJSON, err := json.Marshal(frame);
+	if err != nil {
+		log.Printf("Failed to marshal reactions frame: %v", err);
+		return;
+	}
+
+	sh.broadcastMessage(&BroadcastMessage{SessionID: sh.sessionID, Data: frameJSON});
+}
@@ -2,39 +2,72 @@ package realtime
 
 import (
 	"log"
+	"time"
 )
 
+// run serves the session until stop is closed, when its last client is gone.
 func (sh *SessionHub) run() {
+	reactionTicker := time.NewTicker(reactionFlushInterval);
+	defer reactionTicker.Stop();
+	pulseTicker := time.NewTicker(pulseSampleInterval);
+	defer pulseTicker.Stop();
+
 	for {
 		select {
-		case client := <-sh.register:
-			sh.mutex.Lock();
-			sh.clients[client] = true;
-			sh.mutex.Unlock();
-			log.Printf("Client added to session hub: %s", client.sessionID);
-
-		case client := <-sh.unregister:
-			sh.mutex.Lock();
-			if _, exists := sh.clients[client]; exists {
-				delete(sh.clients, client);
-				log.Printf("Client removed from session hub: %s", client.sessionID);
-			}
-
-			sh.mutex.Unlock();
+		case <-sh.stop:
+			log.Printf("Session hub stopped: %s", sh.sessionID);
+			return;
 
 		case message := <-sh.broadcast:
 			sh.broadcastMessage(message);
+
+		case reaction := <-sh.reactions:
+			sh.reactionCounts[reaction]++;
+
+		case <-reactionTicker.C:
+			sh.flushReactions();
+
+		case update := <-sh.pulses:
+			sh.recordPulse(update);
+
+		case <-pulseTicker.C:
+			sh.flushPulses();
 		}
 	}
 }
 
-func (sh *SessionHub) broadcastMessage(message []byte) {
+// addClient and removeClient run on the hub's goroutine, which also decides when the session hub
+// stops, so a client can never join a session hub that is stopping.
+func (sh *SessionHub) addClient(client *Client) {
+	sh.mutex.Lock();
+	defer sh.mutex.Unlock();
+
+	sh.clients[client] = true;
+	log.Printf("Client added to session hub: %s", client.sessionID);
+}
+
+func (sh *SessionHub) removeClient(client *Client) int {
+	sh.mutex.Lock();
+	defer sh.mutex.Unlock();
+
+	if _, exists := sh.clients[client]; exists {
+		delete(sh.clients, client);
+		log.Printf("Client removed from session hub: %s", client.sessionID);
+	}
+	return len(sh.clients);
+}
+
+func (sh *SessionHub) broadcastMessage(message *BroadcastMessage) {
 	sh.mutex.Lock();
 	defer sh.mutex.Unlock();
 
 	for client := range sh.clients {
+		if !message.accepts(client) {
+			continue;
+		}
+
 		select {
-		case client.send <- message:
+		case client.send <- message.payloadFor(client):
 			// message successfully  sent to client channel.
 
 		default : 
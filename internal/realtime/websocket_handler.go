@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 	"github.com/gorilla/websocket"
+
+	"RealTimePoll/pkg/jwt"
 )
 
 func (h *Hub) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
@@ -34,6 +37,34 @@ func (h *Hub) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
     }
 
 
+	organizerID := "";
+	if claims, ok := r.Context().Value("organizerClaims").(*jwt.Claims); ok && userType == "organizer" {
+		organizerID = claims.OrganizerID;
+	}
+
+	// per-user messages only reach participants whose id is proven by a token,
+	// other participant connections get the session-wide messages.
+	verified := false;
+	voterToken := r.URL.Query().Get("voterToken");
+	participantToken := r.URL.Query().Get("participantToken");
+	if userType == "participant" && (voterToken != "" || participantToken != "") {
+		h.mutex.RLock();
+		verify := h.verifyParticipant;
+		h.mutex.RUnlock();
+		if verify == nil {
+			http.Error(w, "participant verification unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		participantID, err := verify(sessionID, userID, voterToken, participantToken);
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		userID = participantID;
+		verified = true;
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil);
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
@@ -47,6 +78,13 @@ func (h *Hub) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
 		sessionID: sessionID,
 		userType: userType,
 		userID: userID,
+		verified: verified,
+		organizerID: organizerID,
+		reactionLimiter: newReactionLimiter(),
+		pulseLimiter: newPulseLimiter(),
+		votedQuestions: make(map[string]bool),
+		locale: r.URL.Query().Get("locale"),
+		acceptLanguage: r.Header.Get("Accept-Language"),
 	}
 
 	// register client
@@ -171,6 +209,12 @@ func (c *Client) handleMessage(message []byte) {
 	case "get_results":
 		c.handleGetResults();
 
+	case "reaction":
+		c.handleReaction(msg);
+
+	case "pulse":
+		c.handlePulse(msg);
+
 	default:
         log.Printf("Unknown message type: %s", messageType)
         // Send error response
@@ -184,6 +228,20 @@ func (c *Client) handleMessage(message []byte) {
 }
 
 
+// sendError reports a problem with a client message back to that client
+func (c *Client) sendError(message string) {
+    errorMsg := map[string]interface{}{
+        "type": "error",
+        "message": message,
+    }
+    errorJSON, _ := json.Marshal(errorMsg)
+
+    select {
+    case c.send <- errorJSON:
+    default:
+    }
+}
+
 // handleGetResults sends current results to client (placeholder for now)
 func (c *Client) handleGetResults() {
     // This will be implemented when we have results retrieval
@@ -205,6 +263,151 @@ func (h *Hub) BroadcastToSession(sessionID string, message []byte) {
     h.broadcast <- broadcastMsg
 }
 
+// SendToUser sends a message only to the connections of one user in a session
+func (h *Hub) SendToUser(sessionID, userID string, message []byte) {
+    h.broadcast <- &BroadcastMessage{
+        SessionID: sessionID,
+        Data:      message,
+        UserID:    userID,
+    }
+}
+
+// BroadcastWithOrganizerView sends exact to the session's organizer and public to everyone else.
+// exact and localized are optional, localized holds public per locale of a translated session.
+func (h *Hub) BroadcastWithOrganizerView(sessionID, organizerID string, public, exact []byte, localized map[string][]byte) {
+    h.broadcast <- &BroadcastMessage{
+        SessionID:     sessionID,
+        Data:          public,
+        OrganizerID:   organizerID,
+        OrganizerData: exact,
+        Localized:     localized,
+    }
+}
+
+// BroadcastQuestionResults sends results of a question to the clients its visibility policy allows.
+// exact is optional and only reaches the session's organizer, localized as in BroadcastWithOrganizerView.
+func (h *Hub) BroadcastQuestionResults(sessionID, questionID, visibility, organizerID string, public, exact []byte, localized map[string][]byte) {
+    h.broadcast <- &BroadcastMessage{
+        SessionID:     sessionID,
+        Data:          public,
+        OrganizerID:   organizerID,
+        OrganizerData: exact,
+        QuestionID:    questionID,
+        Visibility:    visibility,
+        Localized:     localized,
+    }
+}
+
+// UnvotedParticipantIDs returns verified connected participants not yet known to have voted on the question
+func (h *Hub) UnvotedParticipantIDs(sessionID, questionID string) []string {
+    h.mutex.RLock()
+    defer h.mutex.RUnlock()
+
+    sessionHub, exists := h.sessions[sessionID]
+    if !exists {
+        return nil
+    }
+
+    sessionHub.mutex.RLock()
+    defer sessionHub.mutex.RUnlock()
+
+    seen := make(map[string]bool)
+    userIDs := []string{}
+    for client := range sessionHub.clients {
+        if client.userType != "participant" || !client.verified || client.votedQuestions[questionID] || seen[client.userID] {
+            continue
+        }
+        seen[client.userID] = true
+        userIDs = append(userIDs, client.userID)
+    }
+    return userIDs
+}
+
+// MarkVoted records on the participants' connections that they voted on the question
+func (h *Hub) MarkVoted(sessionID, questionID string, userIDs []string) {
+    if len(userIDs) == 0 {
+        return
+    }
+
+    h.mutex.RLock()
+    defer h.mutex.RUnlock()
+
+    sessionHub, exists := h.sessions[sessionID]
+    if !exists {
+        return
+    }
+
+    voted := make(map[string]bool)
+    for _, userID := range userIDs {
+        voted[userID] = true
+    }
+
+    sessionHub.mutex.Lock()
+    defer sessionHub.mutex.Unlock()
+
+    for client := range sessionHub.clients {
+        if client.verified && voted[client.userID] {
+            client.votedQuestions[questionID] = true
+        }
+    }
+}
+
+// ClearVoted forgets that the participant voted on the question, e.g. after a retraction
+func (h *Hub) ClearVoted(sessionID, questionID, userID string) {
+    h.mutex.RLock()
+    defer h.mutex.RUnlock()
+
+    sessionHub, exists := h.sessions[sessionID]
+    if !exists {
+        return
+    }
+
+    sessionHub.mutex.Lock()
+    defer sessionHub.mutex.Unlock()
+
+    for client := range sessionHub.clients {
+        if strings.EqualFold(client.userID, userID) {
+            delete(client.votedQuestions, questionID)
+        }
+    }
+}
+
+// BroadcastToUserType sends a message to all clients of a session with the given userType
+func (h *Hub) BroadcastToUserType(sessionID, userType string, message []byte) {
+    h.broadcast <- &BroadcastMessage{
+        SessionID: sessionID,
+        Data:      message,
+        UserType:  userType,
+    }
+}
+
+// SessionUserIDs returns the distinct user IDs of the given type connected to a session,
+// for participants only the verified ones, as they are the ones per-user messages reach
+func (h *Hub) SessionUserIDs(sessionID, userType string) []string {
+    h.mutex.RLock()
+    defer h.mutex.RUnlock()
+
+    sessionHub, exists := h.sessions[sessionID]
+    if !exists {
+        return nil
+    }
+
+    sessionHub.mutex.RLock()
+    defer sessionHub.mutex.RUnlock()
+
+    seen := make(map[string]bool)
+    userIDs := []string{}
+    for client := range sessionHub.clients {
+        if client.userType != userType || (userType == "participant" && !client.verified) || seen[client.userID] {
+            continue
+        }
+        seen[client.userID] = true
+        userIDs = append(userIDs, client.userID)
+    }
+
+    return userIDs
+}
+
 // GetSessionStats returns statistics about a session's connections
 func (h *Hub) GetSessionStats(sessionID string) map[string]interface{} {
     h.mutex.RLock()
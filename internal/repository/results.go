@@ -6,7 +6,10 @@ import (
 	"RealTimePoll/internal/utils"
 
 	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"encoding/json"
 	"log"
 	"context"
@@ -154,6 +157,169 @@ func SaveVoteToMongo(vote models.Vote) error {
 }
 
 
+// SaveVotesToMongo stores the votes of one submission all-or-nothing, in a single transaction.
+func SaveVotesToMongo(votes []models.Vote) error {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	documents := make([]interface{}, len(votes));
+	for i, vote := range votes {
+		documents[i] = vote;
+	}
+
+	err := mongoDb.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) error {
+		_, err := votesCollection.InsertMany(sessionCtx, documents);
+		return err;
+	});
+	if err != nil {
+		if isDuplicateKeyError(err) || mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("duplicate vote")
+		}
+		return fmt.Errorf("failed to insert votes: %v", err)
+	}
+
+	log.Printf("%d votes saved to MongoDB", len(votes))
+
+	for _, vote := range votes {
+		if err := cacheVoteInRedis(vote); err != nil {
+			log.Printf("Warning: Failed to cache vote in Redis: %v", err)
+		}
+	}
+
+	return nil
+}
+
+
+// ReviseVote replaces the selection of an existing vote (or empties it for a retraction) and
+// appends the previous selection to its history. The whole revision is a single document update,
+// so the vote moves from the old options to the new ones atomically for the results aggregation.
+// The vote as it was before the revision is returned.
+func ReviseVote(revision models.Vote, action string) (*models.Vote, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	// pipeline updates evaluate "$..." strings and operator keys, so caller values are literals.
+	nowTime := time.Now();
+	update := []bson.M{
+		{"$set": bson.M{
+			"history": bson.M{"$concatArrays": []interface{}{
+				bson.M{"$ifNull": []interface{}{"$history", bson.A{}}},
+				bson.A{bson.M{
+					"selected_options": "$selected_options",
+					"action":           literal(action),
+					"replaced_at":      literal(nowTime),
+				}},
+			}},
+			"selected_options": literal(revision.SelectedOptions),
+			"retracted":        literal(revision.Retracted),
+			"correct":          literal(revision.Correct),
+			"score":            literal(revision.Score),
+			"ballot_hash":      literal(revision.BallotHash),
+			"other_text":       literal(revision.OtherText),
+			"other_text_normalized": literal(revision.OtherTextNormalized),
+			"availability":     literal(revision.Availability),
+			"allocation":       literal(revision.Allocation),
+			"row_answers":      literal(revision.RowAnswers),
+			"updated_at":       literal(nowTime),
+		}},
+	}
+
+	var previous models.Vote;
+	err := votesCollection.FindOneAndUpdate(ctx,
+		bson.M{
+			"session_id":     revision.SessionID,
+			"question_id":    revision.QuestionID,
+			"participant_id": revision.ParticipantID,
+		},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&previous);
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("vote not found");
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revise vote: %v", err);
+	}
+
+	log.Printf("Vote %s revised (%s)", previous.ID.Hex(), action)
+	return &previous, nil;
+}
+
+
+// literal keeps a value from being evaluated as an expression inside an update pipeline.
+func literal(value interface{}) bson.M {
+	return bson.M{"$literal": value};
+}
+
+
+// SaveSecretBallots stores secret ballots and the matching participation records in one transaction.
+// The unique index of participations is the last line of deduplication.
+func SaveSecretBallots(ballots []models.Vote, participations []models.Participation) error {
+	mongoDb := database.GetMongoInstance();
+	participationsCollection := mongoDb.GetCollection(utils.PARTICIPATIONS_COLLECTION);
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	participationDocs := make([]interface{}, len(participations));
+	for i, participation := range participations {
+		participationDocs[i] = participation;
+	}
+	ballotDocs := make([]interface{}, len(ballots));
+	for i, ballot := range ballots {
+		ballotDocs[i] = ballot;
+	}
+
+	err := mongoDb.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) error {
+		if _, err := participationsCollection.InsertMany(sessionCtx, participationDocs); err != nil {
+			return err;
+		}
+		_, err := votesCollection.InsertMany(sessionCtx, ballotDocs);
+		return err;
+	});
+	if err != nil {
+		if isDuplicateKeyError(err) || mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("duplicate vote");
+		}
+		return fmt.Errorf("failed to insert secret ballots: %v", err);
+	}
+
+	log.Printf("%d secret ballots saved to MongoDB", len(ballots))
+	return nil;
+}
+
+// CountParticipations returns how many participants voted on a question of a secret ballot session.
+func CountParticipations(sessionID, questionID primitive.ObjectID) (int, error) {
+	mongoDb := database.GetMongoInstance();
+	participationsCollection := mongoDb.GetCollection(utils.PARTICIPATIONS_COLLECTION);
+
+	count, err := participationsCollection.CountDocuments(context.Background(), bson.M{
+		"session_id":  sessionID,
+		"question_id": questionID,
+	});
+	if err != nil {
+		return 0, fmt.Errorf("failed to count participations: %v", err);
+	}
+	return int(count), nil;
+}
+
+// CountSessionVotes returns the number of (non retracted) votes cast in a session.
+func CountSessionVotes(sessionID primitive.ObjectID) (int64, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+
+	count, err := votesCollection.CountDocuments(context.Background(), bson.M{
+		"session_id": sessionID,
+		"retracted":  bson.M{"$ne": true},
+	});
+	if err != nil {
+		return 0, fmt.Errorf("failed to count session votes: %v", err);
+	}
+	return count, nil;
+}
+
+
 func cacheVoteInRedis(vote models.Vote) error {
     redisDb := database.GetRedisInstance()
     ctx := context.Background();
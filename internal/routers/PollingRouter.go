@@ -22,6 +22,27 @@ func RegisterCoreRouters(apiRouter *mux.Router) {
 	})
 
 	apiRouter.HandleFunc("/sessions", handlers.CreateNewPoll).Methods("POST");
+	apiRouter.HandleFunc("/sessions/import", handlers.ImportSessionHandler).Methods("POST");
+	apiRouter.HandleFunc("/images", handlers.UploadImageHandler).Methods("POST");
+	apiRouter.HandleFunc("/templates", handlers.SaveTemplateHandler).Methods("POST");
+	apiRouter.HandleFunc("/templates", handlers.ListTemplatesHandler).Methods("GET");
+	apiRouter.HandleFunc("/templates/{templateId}", handlers.DeleteTemplateHandler).Methods("DELETE");
+	apiRouter.HandleFunc("/templates/{templateId}/sessions", handlers.CreateSessionFromTemplateHandler).Methods("POST");
+	apiRouter.HandleFunc("/bank/questions", handlers.AddBankQuestionHandler).Methods("POST");
+	apiRouter.HandleFunc("/bank/questions", handlers.ListBankQuestionsHandler).Methods("GET");
+	apiRouter.HandleFunc("/bank/questions/{bankQuestionId}", handlers.DeleteBankQuestionHandler).Methods("DELETE");
+	apiRouter.HandleFunc("/bank/sessions", handlers.CreateSessionFromBankHandler).Methods("POST");
+	apiRouter.HandleFunc("/sessions/{sessionId}/questions/{questionId}/open", handlers.OpenQuestionHandler).Methods("PATCH");
+	apiRouter.HandleFunc("/sessions/{sessionId}/questions/{questionId}/close", handlers.CloseQuestionHandler).Methods("PATCH");
+	apiRouter.HandleFunc("/sessions/{sessionId}/questions/{questionId}/other-texts", handlers.OtherTextsHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/questions/{questionId}/pulse", handlers.PulseTimelineHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/qna/moderation", handlers.ModerationQueueHandler).Methods("GET");
+	apiRouter.HandleFunc("/qna/{qnaId}", handlers.ModerateQuestionHandler).Methods("PATCH");
+	apiRouter.HandleFunc("/sessions/{sessionId}/roster", handlers.UploadRosterHandler).Methods("PUT");
+	apiRouter.HandleFunc("/sessions/{sessionId}/roster", handlers.GetRosterHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/roster/turnout", handlers.TurnoutHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/roster/{entryId}/invite", handlers.ReissueInviteHandler).Methods("POST");
+	apiRouter.HandleFunc("/sessions/{sessionId}/export", handlers.ExportSessionHandler).Methods("GET");
 }
 
 
@@ -29,7 +50,24 @@ func RegisterVotingRouters(apiRouter *mux.Router) {
 	
 
 	apiRouter.HandleFunc("/votes", handlers.SubmitVoteHandler).Methods("POST");
+	apiRouter.HandleFunc("/votes/batch", handlers.SubmitSurveyHandler).Methods("POST");
+	apiRouter.HandleFunc("/sessions/{sessionId}/invites/redeem", handlers.RedeemInviteHandler).Methods("POST");
+	apiRouter.HandleFunc("/sessions/{sessionId}/participants", handlers.JoinSessionHandler).Methods("POST");
+	apiRouter.HandleFunc("/votes", handlers.RetractVoteHandler).Methods("DELETE");
 	apiRouter.HandleFunc("/status", handlers.UpdateSessionHandler).Methods("PATCH");
+	apiRouter.HandleFunc("/sessions/{sessionId}", handlers.GetSessionHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/leaderboard", handlers.LeaderboardHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/next", handlers.NextQuestionHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/qna", handlers.AskQuestionHandler).Methods("POST");
+	apiRouter.HandleFunc("/sessions/{sessionId}/qna", handlers.ListQuestionsHandler).Methods("GET");
+	apiRouter.HandleFunc("/qna/{qnaId}/upvote", handlers.UpvoteQuestionHandler).Methods("POST");
+	apiRouter.HandleFunc("/votes/{voteId}/receipt", handlers.GetReceiptHandler).Methods("GET");
+	apiRouter.HandleFunc("/receipts/public-key", handlers.ReceiptPublicKeyHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/audit", handlers.TallyAuditHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/questions/{questionId}/proof", handlers.InclusionProofHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/questions/{questionId}/seats", handlers.SeatsHandler).Methods("GET");
+	apiRouter.HandleFunc("/sessions/{sessionId}/questions/{questionId}/schedule.ics", handlers.ScheduleICSHandler).Methods("GET");
+	apiRouter.HandleFunc("/blobs/{key:.+}", handlers.BlobHandler).Methods("GET");
 }
 
 func RegisterWebsocketRoutes(apiRouter *mux.Router, hub *realtime.Hub) {
@@ -38,6 +76,7 @@ func RegisterWebsocketRoutes(apiRouter *mux.Router, hub *realtime.Hub) {
 		return jwt.Middleware(h.ServeHTTP);
 	});
 
+	hub.SetParticipantVerifier(handlers.VerifyParticipantConnection);
 	apiRouter.HandleFunc("/ws", hub.ServeWebSocket);
 
 	apiRouter.HandleFunc("/api/v1/ws/stats", func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SumAllocations returns the points given to each option of an allocation question, by option index.
+func SumAllocations(sessionID, questionID primitive.ObjectID) (map[int]int, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"session_id":  sessionID,
+			"question_id": questionID,
+			"retracted":   bson.M{"$ne": true},
+		}},
+		{"$unwind": bson.M{"path": "$allocation", "includeArrayIndex": "option"}},
+		{"$group": bson.M{
+			"_id":    "$option",
+			"points": bson.M{"$sum": "$allocation"},
+		}},
+	}
+
+	cursor, err := votesCollection.Aggregate(ctx, pipeline);
+	if err != nil {
+		return nil, fmt.Errorf("allocation aggregation failed: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	points := make(map[int]int);
+	for cursor.Next(ctx) {
+		var result struct {
+			Option int `bson:"_id"`
+			Points int `bson:"points"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			continue;
+		}
+		points[result.Option] = result.Points;
+	}
+	return points, nil;
+}
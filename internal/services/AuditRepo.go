@@ -0,0 +1,155 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	VOTE_RECEIPT_PREFIX = "vote_receipt:"
+)
+
+// receiptKey needs both the vote and the participant id, so a guessed vote id alone does not
+// reveal a selection. The participant id itself is not stored.
+func receiptKey(voteID, participantID string) string {
+	return VOTE_RECEIPT_PREFIX + utils.KeyedHash(utils.BallotHashKey(), voteID, participantID);
+}
+
+// SaveVoteReceipt keeps the receipt in redis so a participant who missed the websocket
+// message can still fetch it by vote id.
+func SaveVoteReceipt(receipt models.VoteReceipt, participantID string) error {
+	redisDb := database.GetRedisInstance();
+
+	receiptJSON, err := json.Marshal(receipt);
+	if err != nil {
+		return fmt.Errorf("failed to marshal receipt: %v", err);
+	}
+
+	if err := redisDb.GetClient().Set(context.Background(), receiptKey(receipt.VoteID, participantID), receiptJSON, utils.VOTE_RECEIPT_TTL).Err(); err != nil {
+		return fmt.Errorf("failed to cache receipt: %v", err);
+	}
+	return nil;
+}
+
+func GetVoteReceipt(voteID, participantID string) (*models.VoteReceipt, error) {
+	redisDb := database.GetRedisInstance();
+
+	receiptJSON, err := redisDb.GetClient().Get(context.Background(), receiptKey(voteID, participantID)).Result();
+	if err != nil {
+		return nil, fmt.Errorf("receipt not found");
+	}
+
+	var receipt models.VoteReceipt;
+	if err := json.Unmarshal([]byte(receiptJSON), &receipt); err != nil {
+		return nil, fmt.Errorf("failed to decode receipt: %v", err);
+	}
+	return &receipt, nil;
+}
+
+// GetBallotHashes returns the ballot hashes counted for a question, sorted so the merkle tree
+// does not depend on insert order.
+func GetBallotHashes(sessionID, questionID primitive.ObjectID) ([]string, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	cursor, err := votesCollection.Find(ctx,
+		bson.M{
+			"session_id":  sessionID,
+			"question_id": questionID,
+			"retracted":   bson.M{"$ne": true},
+			"ballot_hash": bson.M{"$exists": true, "$ne": ""},
+		},
+		options.Find().SetProjection(bson.M{"ballot_hash": 1}).SetSort(bson.M{"ballot_hash": 1}),
+	);
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ballot hashes: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	hashes := []string{};
+	for cursor.Next(ctx) {
+		var ballot struct {
+			BallotHash string `bson:"ballot_hash"`
+		}
+		if err := cursor.Decode(&ballot); err != nil {
+			return nil, fmt.Errorf("failed to decode ballot hash: %v", err);
+		}
+		hashes = append(hashes, ballot.BallotHash);
+	}
+	return hashes, cursor.Err();
+}
+
+// SaveTallyAudits publishes the merkle roots of a session. Closing a reopened session again
+// replaces the roots, so proofs are always checked against the ballots of the last close.
+func SaveTallyAudits(audits []models.TallyAudit) error {
+	mongoDb := database.GetMongoInstance();
+	auditsCollection := mongoDb.GetCollection(utils.TALLY_AUDITS_COLLECTION);
+	ctx := context.Background();
+
+	for _, audit := range audits {
+		_, err := auditsCollection.UpdateOne(ctx,
+			bson.M{"session_id": audit.SessionID, "question_id": audit.QuestionID},
+			bson.M{
+				"$set": bson.M{
+					"merkle_root":  audit.MerkleRoot,
+					"leaf_count":   audit.LeafCount,
+					"published_at": audit.PublishedAt,
+					"signature":    audit.Signature,
+				},
+				"$setOnInsert": bson.M{"_id": audit.ID},
+			},
+			options.Update().SetUpsert(true),
+		);
+		if err != nil {
+			return fmt.Errorf("failed to save tally audit: %v", err);
+		}
+	}
+
+	log.Printf("%d tally audits published", len(audits))
+	return nil;
+}
+
+func GetTallyAudit(sessionID, questionID primitive.ObjectID) (*models.TallyAudit, error) {
+	mongoDb := database.GetMongoInstance();
+	auditsCollection := mongoDb.GetCollection(utils.TALLY_AUDITS_COLLECTION);
+
+	var audit models.TallyAudit;
+	err := auditsCollection.FindOne(context.Background(), bson.M{"session_id": sessionID, "question_id": questionID}).Decode(&audit);
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("tally not published yet");
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tally audit: %v", err);
+	}
+	return &audit, nil;
+}
+
+func ListTallyAudits(sessionID primitive.ObjectID) ([]models.TallyAudit, error) {
+	mongoDb := database.GetMongoInstance();
+	auditsCollection := mongoDb.GetCollection(utils.TALLY_AUDITS_COLLECTION);
+	ctx := context.Background();
+
+	cursor, err := auditsCollection.Find(ctx, bson.M{"session_id": sessionID});
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tally audits: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	audits := []models.TallyAudit{};
+	if err := cursor.All(ctx, &audits); err != nil {
+		return nil, fmt.Errorf("failed to decode tally audits: %v", err);
+	}
+	return audits, nil;
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetParticipantAnswers returns the current (not retracted) answers of a participant in a session.
+func GetParticipantAnswers(sessionID primitive.ObjectID, participantID string) (models.ParticipantAnswers, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	participantObjID, err := primitive.ObjectIDFromHex(participantID);
+	if err != nil {
+		return nil, fmt.Errorf("invalid participant ID: %v", err);
+	}
+
+	cursor, err := votesCollection.Find(ctx,
+		bson.M{
+			"session_id":     sessionID,
+			"participant_id": participantObjID,
+			"retracted":      bson.M{"$ne": true},
+		},
+		options.Find().SetProjection(bson.M{"question_id": 1, "selected_options": 1}),
+	);
+	if err != nil {
+		return nil, fmt.Errorf("failed to find participant answers: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	answers := make(models.ParticipantAnswers);
+	for cursor.Next(ctx) {
+		var vote models.Vote;
+		if err := cursor.Decode(&vote); err != nil {
+			return nil, fmt.Errorf("failed to decode participant answer: %v", err);
+		}
+		answers[vote.QuestionID] = vote.SelectedOptions;
+	}
+	return answers, cursor.Err();
+}
+
+// VotedParticipantIDs returns which of the participants currently hold a (not retracted) vote
+// on the question. Ids that are not valid object ids are left out.
+func VotedParticipantIDs(sessionID, questionID primitive.ObjectID, participantIDs []string) ([]string, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+
+	byObjID := make(map[primitive.ObjectID][]string, len(participantIDs));
+	objIDs := []primitive.ObjectID{};
+	for _, participantID := range participantIDs {
+		participantObjID, err := primitive.ObjectIDFromHex(participantID);
+		if err != nil {
+			continue;
+		}
+		if _, seen := byObjID[participantObjID]; !seen {
+			objIDs = append(objIDs, participantObjID);
+		}
+		byObjID[participantObjID] = append(byObjID[participantObjID], participantID);
+	}
+	if len(objIDs) == 0 {
+		return []string{}, nil;
+	}
+
+	values, err := votesCollection.Distinct(context.Background(), "participant_id", bson.M{
+		"session_id":     sessionID,
+		"question_id":    questionID,
+		"participant_id": bson.M{"$in": objIDs},
+		"retracted":      bson.M{"$ne": true},
+	});
+	if err != nil {
+		return nil, fmt.Errorf("failed to find voted participants: %v", err);
+	}
+
+	voted := []string{};
+	for _, value := range values {
+		if participantObjID, ok := value.(primitive.ObjectID); ok {
+			voted = append(voted, byObjID[participantObjID]...);
+		}
+	}
+	return voted, nil;
+}
+
+// RecordQuestionView remembers that a question was shown to a participant. It is idempotent.
+func RecordQuestionView(sessionID, questionID primitive.ObjectID, participantID string) error {
+	mongoDb := database.GetMongoInstance();
+	viewsCollection := mongoDb.GetCollection(utils.QUESTION_VIEWS_COLLECTION);
+
+	filter := bson.M{
+		"session_id":     sessionID,
+		"question_id":    questionID,
+		"participant_id": participantID,
+	};
+	_, err := viewsCollection.UpdateOne(context.Background(),
+		filter,
+		bson.M{"$setOnInsert": bson.M{"shown_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	);
+	if err != nil {
+		return fmt.Errorf("failed to record question view: %v", err);
+	}
+	return nil;
+}
+
+// CountQuestionViews returns how many participants a question was shown to.
+func CountQuestionViews(sessionID, questionID primitive.ObjectID) (int, error) {
+	mongoDb := database.GetMongoInstance();
+	viewsCollection := mongoDb.GetCollection(utils.QUESTION_VIEWS_COLLECTION);
+
+	count, err := viewsCollection.CountDocuments(context.Background(), bson.M{
+		"session_id":  sessionID,
+		"question_id": questionID,
+	});
+	if err != nil {
+		return 0, fmt.Errorf("failed to count question views: %v", err);
+	}
+	return int(count), nil;
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OPTION_SEATS_PREFIX = "option_seats:"
+	OPTION_WAITLIST_PREFIX = "option_waitlist:"
+)
+
+// reserveSeatsScript takes one seat on every key, all or none. KEYS are the seat counters and
+// ARGV their capacities. It returns 0 on success, or the 1-based position of the first full key.
+var reserveSeatsScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	if tonumber(redis.call('GET', key) or '0') >= tonumber(ARGV[i]) then
+		return i
+	end
+end
+for _, key in ipairs(KEYS) do
+	redis.call('INCR', key)
+end
+return 0
+`)
+
+// the {session:question} hash tag keeps all counters of a question in one cluster slot.
+func seatsKey(sessionID, questionID primitive.ObjectID, option int) string {
+	return fmt.Sprintf("%s{%s:%s}:%d", OPTION_SEATS_PREFIX, sessionID.Hex(), questionID.Hex(), option);
+}
+
+func waitlistKey(sessionID, questionID primitive.ObjectID, option int) string {
+	return fmt.Sprintf("%s%s:%s:%d", OPTION_WAITLIST_PREFIX, sessionID.Hex(), questionID.Hex(), option);
+}
+
+// ReserveSeats takes a seat on every capacity-limited option of the selection atomically, so
+// concurrent consumers cannot overbook. It returns the first full option, or -1 when seated.
+func ReserveSeats(sessionID, questionID primitive.ObjectID, capacities []int, selected []int) (int, error) {
+	redisDb := database.GetRedisInstance();
+	ctx := context.Background();
+
+	keys := []string{};
+	args := []interface{}{};
+	limited := []int{};
+	for _, option := range selected {
+		if option < len(capacities) && capacities[option] > 0 {
+			if err := syncSeatCounter(sessionID, questionID, option); err != nil {
+				return -1, err;
+			}
+			keys = append(keys, seatsKey(sessionID, questionID, option));
+			args = append(args, capacities[option]);
+			limited = append(limited, option);
+		}
+	}
+
+	if len(keys) == 0 {
+		return -1, nil;
+	}
+
+	full, err := reserveSeatsScript.Run(ctx, redisDb.GetClient(), keys, args...).Int();
+	if err != nil {
+		return -1, fmt.Errorf("redis error: %v", err);
+	}
+	if full > 0 {
+		return limited[full-1], nil;
+	}
+	return -1, nil;
+}
+
+// ReleaseSeats gives back the seats of the capacity-limited options among the given ones.
+func ReleaseSeats(sessionID, questionID primitive.ObjectID, capacities []int, options []int) {
+	redisDb := database.GetRedisInstance();
+	ctx := context.Background();
+
+	for _, option := range options {
+		if option < len(capacities) && capacities[option] > 0 {
+			key := seatsKey(sessionID, questionID, option);
+			if taken, err := redisDb.GetClient().Decr(ctx, key).Result(); err == nil && taken < 0 {
+				redisDb.GetClient().Set(ctx, key, 0, 0);
+			}
+		}
+	}
+}
+
+// GetTakenSeats returns the seats taken on an option, from redis or counted from the votes.
+func GetTakenSeats(sessionID, questionID primitive.ObjectID, option int) (int, error) {
+	if err := syncSeatCounter(sessionID, questionID, option); err != nil {
+		return 0, err;
+	}
+
+	redisDb := database.GetRedisInstance();
+	taken, err := redisDb.GetClient().Get(context.Background(), seatsKey(sessionID, questionID, option)).Int();
+	if err != nil {
+		return 0, fmt.Errorf("redis error: %v", err);
+	}
+	return taken, nil;
+}
+
+// syncSeatCounter seeds a missing counter from the stored votes, e.g. after a redis restart.
+func syncSeatCounter(sessionID, questionID primitive.ObjectID, option int) error {
+	redisDb := database.GetRedisInstance();
+	ctx := context.Background();
+
+	key := seatsKey(sessionID, questionID, option);
+	exists, err := redisDb.GetClient().Exists(ctx, key).Result();
+	if err != nil {
+		return fmt.Errorf("redis error: %v", err);
+	}
+	if exists > 0 {
+		return nil;
+	}
+
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	taken, err := votesCollection.CountDocuments(ctx, bson.M{
+		"session_id":       sessionID,
+		"question_id":      questionID,
+		"selected_options": option,
+		"retracted":        bson.M{"$ne": true},
+	});
+	if err != nil {
+		return fmt.Errorf("failed to count taken seats: %v", err);
+	}
+
+	return redisDb.GetClient().SetNX(ctx, key, taken, 0).Err();
+}
+
+// AddToWaitlist queues a vote for a full option and returns its 1-based position.
+func AddToWaitlist(sessionID, questionID primitive.ObjectID, option int, entry []byte) (int, error) {
+	redisDb := database.GetRedisInstance();
+
+	length, err := redisDb.GetClient().RPush(context.Background(), waitlistKey(sessionID, questionID, option), entry).Result();
+	if err != nil {
+		return 0, fmt.Errorf("failed to join waitlist: %v", err);
+	}
+	return int(length), nil;
+}
+
+// PopWaitlist returns the oldest queued vote of an option, nil when nobody waits.
+func PopWaitlist(sessionID, questionID primitive.ObjectID, option int) ([]byte, error) {
+	redisDb := database.GetRedisInstance();
+
+	entry, err := redisDb.GetClient().LPop(context.Background(), waitlistKey(sessionID, questionID, option)).Bytes();
+	if err == redis.Nil {
+		return nil, nil;
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read waitlist: %v", err);
+	}
+	return entry, nil;
+}
+
+func GetWaitlistLength(sessionID, questionID primitive.ObjectID, option int) (int, error) {
+	redisDb := database.GetRedisInstance();
+
+	length, err := redisDb.GetClient().LLen(context.Background(), waitlistKey(sessionID, questionID, option)).Result();
+	if err != nil {
+		return 0, fmt.Errorf("failed to read waitlist: %v", err);
+	}
+	return int(length), nil;
+}
+
+// GetWaitlistPosition returns the 1-based position of a participant on an option's waitlist, 0 if absent.
+func GetWaitlistPosition(sessionID, questionID primitive.ObjectID, option int, participantID string) (int, error) {
+	redisDb := database.GetRedisInstance();
+
+	entries, err := redisDb.GetClient().LRange(context.Background(), waitlistKey(sessionID, questionID, option), 0, -1).Result();
+	if err != nil {
+		return 0, fmt.Errorf("failed to read waitlist: %v", err);
+	}
+
+	for i, entry := range entries {
+		var queued struct {
+			ParticipantID string `json:"participantId"`
+		}
+		if json.Unmarshal([]byte(entry), &queued) == nil && queued.ParticipantID == participantID {
+			return i + 1, nil;
+		}
+	}
+	return 0, nil;
+}
@@ -216,4 +216,84 @@ func GetVotesForSession(sessionID primitive.ObjectID) ([]string, error) {
     }
 
     return voteIDs, nil
-}
\ No newline at end of file
+}
+
+// SetQuestionOpened stamps the time a question was opened to answers. A reopened question
+// loses its closed_at, so it accepts answers again.
+func SetQuestionOpened(sessionID, questionID primitive.ObjectID) error {
+	return setQuestionTimestamp(sessionID, questionID, "opened_at", "closed_at")
+}
+
+// SetQuestionClosed stamps the time a question stopped accepting answers.
+func SetQuestionClosed(sessionID, questionID primitive.ObjectID) error {
+	return setQuestionTimestamp(sessionID, questionID, "closed_at", "")
+}
+
+// setQuestionTimestamp stamps field of the question with the current time and removes unset, if given.
+func setQuestionTimestamp(sessionID, questionID primitive.ObjectID, field, unset string) error {
+	mongoDb := database.GetMongoInstance();
+	sessionsCollection := mongoDb.GetCollection(utils.SESSION_COLLECTION)
+	ctx := context.Background()
+
+	nowTime, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339));
+	update := map[string]interface{}{
+		"$set": map[string]interface{}{
+			"questions.$." + field: nowTime,
+			"updated_at":          nowTime,
+		},
+	}
+	if unset != "" {
+		update["$unset"] = map[string]interface{}{"questions.$." + unset: ""}
+	}
+
+	result, err := sessionsCollection.UpdateOne(
+		ctx,
+		map[string]interface{}{"_id": sessionID, "questions.id": questionID},
+		update,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update question %s in MongoDB: %v", field, err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("question not found in session")
+	}
+
+	sessionKey := SESSION_KEY_PREFIX + sessionID.Hex();
+	redisDb := database.GetRedisInstance();
+	redisDb.GetClient().Del(ctx, sessionKey);
+
+	log.Printf("Question %s of session %s updated: %s", questionID.Hex(), sessionID.Hex(), field)
+	return nil
+}
+
+
+// FindSessionsWithDueDeadlines returns active sessions whose own deadline, or the deadline
+// of one of their open questions, has passed.
+func FindSessionsWithDueDeadlines(now time.Time) ([]models.Session, error) {
+	mongoDb := database.GetMongoInstance();
+	sessionsCollection := mongoDb.GetCollection(utils.SESSION_COLLECTION)
+	ctx := context.Background()
+
+	cursor, err := sessionsCollection.Find(ctx, map[string]interface{}{
+		"status": utils.ACTIVE,
+		"$or": []interface{}{
+			map[string]interface{}{"closing_rules.deadline": map[string]interface{}{"$lte": now}},
+			map[string]interface{}{"questions": map[string]interface{}{"$elemMatch": map[string]interface{}{
+				"closing_rules.deadline": map[string]interface{}{"$lte": now},
+				"closed_at":              map[string]interface{}{"$exists": false},
+			}}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions with due deadlines: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to decode sessions: %v", err)
+	}
+	return sessions, nil
+}
+
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const exportBatchSize = 500;
+
+// StreamSessionVotes hands every vote of the session to fn, in storage order, straight from a cursor
+// so that large sessions are never loaded at once. It stops at the first error of fn or when ctx ends.
+func StreamSessionVotes(ctx context.Context, sessionID primitive.ObjectID, fn func(models.Vote) error) error {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+
+	cursor, err := votesCollection.Find(ctx, bson.M{"session_id": sessionID},
+		options.Find().SetBatchSize(exportBatchSize).SetProjection(bson.M{"history": 0}));
+	if err != nil {
+		return fmt.Errorf("failed to find votes: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	for cursor.Next(ctx) {
+		var vote models.Vote;
+		if err := cursor.Decode(&vote); err != nil {
+			return fmt.Errorf("failed to decode vote: %v", err);
+		}
+		if err := fn(vote); err != nil {
+			return err;
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("votes cursor failed: %v", err);
+	}
+	return nil;
+}
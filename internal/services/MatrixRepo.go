@@ -0,0 +1,58 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CountRowAnswers returns how often each column was picked on each row of a matrix question,
+// as row index -> column index -> count.
+func CountRowAnswers(sessionID, questionID primitive.ObjectID) (map[int]map[int]int, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"session_id":  sessionID,
+			"question_id": questionID,
+			"retracted":   bson.M{"$ne": true},
+		}},
+		{"$unwind": bson.M{"path": "$row_answers", "includeArrayIndex": "row"}},
+		{"$group": bson.M{
+			"_id":   bson.M{"row": "$row", "column": "$row_answers"},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := votesCollection.Aggregate(ctx, pipeline);
+	if err != nil {
+		return nil, fmt.Errorf("row answers aggregation failed: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	counts := make(map[int]map[int]int);
+	for cursor.Next(ctx) {
+		var result struct {
+			ID struct {
+				Row int `bson:"row"`
+				Column int `bson:"column"`
+			} `bson:"_id"`
+			Count int `bson:"count"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			continue;
+		}
+		if counts[result.ID.Row] == nil {
+			counts[result.ID.Row] = make(map[int]int);
+		}
+		counts[result.ID.Row][result.ID.Column] = result.Count;
+	}
+	return counts, nil;
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GroupOtherTexts returns the free-text answers of a question grouped by normalized text, most
+// frequent first. Variants keeps the distinct spellings as participants typed them.
+func GroupOtherTexts(sessionID, questionID primitive.ObjectID) ([]models.OtherTextGroup, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"session_id":            sessionID,
+			"question_id":           questionID,
+			"retracted":             bson.M{"$ne": true},
+			"other_text_normalized": bson.M{"$nin": bson.A{nil, ""}},
+		}},
+		{"$group": bson.M{
+			"_id":      "$other_text_normalized",
+			"count":    bson.M{"$sum": 1},
+			"variants": bson.M{"$addToSet": "$other_text"},
+		}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+
+	cursor, err := votesCollection.Aggregate(ctx, pipeline);
+	if err != nil {
+		return nil, fmt.Errorf("failed to group other texts: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	groups := []models.OtherTextGroup{};
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode other texts: %v", err);
+	}
+	return groups, nil;
+}
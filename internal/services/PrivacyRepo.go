@@ -0,0 +1,72 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PUBLIC_RESULTS_PREFIX = "public_results:"
+)
+
+// SpendPrivacyBudget takes epsilon from the session's budget. It returns false once the budget
+// would be exceeded. The spent total lives on the session document so it survives restarts.
+func SpendPrivacyBudget(sessionID primitive.ObjectID, epsilon, budget float64) (bool, error) {
+	mongoDb := database.GetMongoInstance();
+	sessionsCollection := mongoDb.GetCollection(utils.SESSION_COLLECTION);
+
+	result, err := sessionsCollection.UpdateOne(context.Background(),
+		bson.M{
+			"_id": sessionID,
+			"$expr": bson.M{"$lte": bson.A{
+				bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$privacy_spent", 0}}, epsilon}},
+				budget,
+			}},
+		},
+		bson.M{"$inc": bson.M{"privacy_spent": epsilon}},
+	);
+	if err != nil {
+		return false, fmt.Errorf("failed to spend privacy budget: %v", err);
+	}
+	return result.ModifiedCount == 1, nil;
+}
+
+// CachePublicResults keeps the last noisy release, repeated once the budget is spent.
+func CachePublicResults(sessionID, questionID primitive.ObjectID, results models.QuestionResult) error {
+	redisDb := database.GetRedisInstance();
+
+	resultsJSON, err := json.Marshal(results);
+	if err != nil {
+		return fmt.Errorf("failed to marshal public results: %v", err);
+	}
+
+	resultsKey := fmt.Sprintf("%s%s:%s", PUBLIC_RESULTS_PREFIX, sessionID.Hex(), questionID.Hex());
+	if err := redisDb.GetClient().Set(context.Background(), resultsKey, resultsJSON, SessionCacheTTL).Err(); err != nil {
+		return fmt.Errorf("failed to cache public results: %v", err);
+	}
+	return nil;
+}
+
+func GetPublicResults(sessionID, questionID primitive.ObjectID) (*models.QuestionResult, error) {
+	redisDb := database.GetRedisInstance();
+
+	resultsKey := fmt.Sprintf("%s%s:%s", PUBLIC_RESULTS_PREFIX, sessionID.Hex(), questionID.Hex());
+	resultsJSON, err := redisDb.GetClient().Get(context.Background(), resultsKey).Result();
+	if err != nil {
+		return nil, fmt.Errorf("public results not found");
+	}
+
+	var results models.QuestionResult;
+	if err := json.Unmarshal([]byte(resultsJSON), &results); err != nil {
+		return nil, fmt.Errorf("failed to decode public results: %v", err);
+	}
+	return &results, nil;
+}
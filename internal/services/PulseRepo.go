@@ -0,0 +1,60 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func SavePulseSample(sample models.PulseSample) error {
+	mongoDb := database.GetMongoInstance();
+	pulseCollection := mongoDb.GetCollection(utils.PULSE_SAMPLES_COLLECTION);
+
+	if sample.ID.IsZero() {
+		sample.ID = primitive.NewObjectID();
+	}
+	if _, err := pulseCollection.InsertOne(context.Background(), sample); err != nil {
+		return fmt.Errorf("failed to save pulse sample: %v", err);
+	}
+	return nil;
+}
+
+// ListPulseSamples returns the sampled time series of a pulse question, oldest first. Zero from/to leave
+// that end open.
+func ListPulseSamples(sessionID, questionID primitive.ObjectID, from, to time.Time) ([]models.PulseSample, error) {
+	mongoDb := database.GetMongoInstance();
+	pulseCollection := mongoDb.GetCollection(utils.PULSE_SAMPLES_COLLECTION);
+	ctx := context.Background();
+
+	filter := bson.M{"session_id": sessionID, "question_id": questionID};
+	at := bson.M{};
+	if !from.IsZero() {
+		at["$gte"] = from;
+	}
+	if !to.IsZero() {
+		at["$lte"] = to;
+	}
+	if len(at) > 0 {
+		filter["at"] = at;
+	}
+
+	cursor, err := pulseCollection.Find(ctx, filter, options.Find().SetSort(bson.M{"at": 1}));
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pulse samples: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	samples := []models.PulseSample{};
+	if err := cursor.All(ctx, &samples); err != nil {
+		return nil, fmt.Errorf("failed to decode pulse samples: %v", err);
+	}
+	return samples, nil;
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	QNA_UPVOTE_LOCK_PREFIX = "qna_upvote_lock:"
+)
+
+func SaveAudienceQuestion(question models.AudienceQuestion) error {
+	mongoDb := database.GetMongoInstance();
+	qnaCollection := mongoDb.GetCollection(utils.QNA_COLLECTION);
+	ctx := context.Background();
+
+	if question.UpvoterIDs == nil {
+		question.UpvoterIDs = []string{};
+	}
+
+	_, err := qnaCollection.InsertOne(ctx, question);
+	if err != nil {
+		return fmt.Errorf("failed to save audience question: %v", err);
+	}
+
+	log.Printf("Audience question %s saved for session %s", question.ID.Hex(), question.SessionID.Hex())
+	return nil;
+}
+
+func GetAudienceQuestion(questionID primitive.ObjectID) (*models.AudienceQuestion, error) {
+	mongoDb := database.GetMongoInstance();
+	qnaCollection := mongoDb.GetCollection(utils.QNA_COLLECTION);
+	ctx := context.Background();
+
+	var question models.AudienceQuestion;
+	err := qnaCollection.FindOne(ctx, bson.M{"_id": questionID}).Decode(&question);
+	if err != nil {
+		return nil, fmt.Errorf("audience question not found: %v", err);
+	}
+
+	return &question, nil;
+}
+
+// ListAudienceQuestions returns the ranked Q&A of a session. Without the moderation queue
+// only approved and answered questions are returned.
+func ListAudienceQuestions(sessionID primitive.ObjectID, withModerationQueue bool) ([]models.AudienceQuestion, error) {
+	mongoDb := database.GetMongoInstance();
+	qnaCollection := mongoDb.GetCollection(utils.QNA_COLLECTION);
+	ctx := context.Background();
+
+	filter := bson.M{"session_id": sessionID};
+	if !withModerationQueue {
+		filter["status"] = bson.M{"$in": []string{utils.QNA_APPROVED, utils.QNA_ANSWERED}};
+	}
+
+	cursor, err := qnaCollection.Find(ctx, filter);
+	if err != nil {
+		return nil, fmt.Errorf("failed to find audience questions: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	questions := []models.AudienceQuestion{};
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, fmt.Errorf("failed to decode audience questions: %v", err);
+	}
+
+	RankAudienceQuestions(questions, time.Now());
+	return questions, nil;
+}
+
+// UpvoteAudienceQuestion counts one upvote per participant. Duplicates are caught by a redis lock
+// first (same as vote deduplication) and by the upvoter list in MongoDB as a fallback.
+func UpvoteAudienceQuestion(questionID primitive.ObjectID, participantID string) error {
+	redisDb := database.GetRedisInstance();
+	ctx := context.Background();
+
+	upvoteLockKey := fmt.Sprintf("%s%s:%s", QNA_UPVOTE_LOCK_PREFIX, questionID.Hex(), participantID);
+	acquired, err := redisDb.GetClient().SetNX(ctx, upvoteLockKey, "1", SessionCacheTTL).Result();
+	if err != nil {
+		return fmt.Errorf("redis error: %v", err);
+	}
+
+	if !acquired {
+		return fmt.Errorf("already upvoted");
+	}
+
+	mongoDb := database.GetMongoInstance();
+	qnaCollection := mongoDb.GetCollection(utils.QNA_COLLECTION);
+
+	result, err := qnaCollection.UpdateOne(ctx,
+		bson.M{
+			"_id": questionID,
+			"upvoter_ids": bson.M{"$ne": participantID},
+		},
+		bson.M{
+			"$inc": bson.M{"upvotes": 1},
+			"$push": bson.M{"upvoter_ids": participantID},
+		},
+	);
+	if err != nil {
+		redisDb.GetClient().Del(ctx, upvoteLockKey);
+		return fmt.Errorf("failed to upvote audience question: %v", err);
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("already upvoted");
+	}
+
+	return nil;
+}
+
+// ModerateAudienceQuestion applies an organizer action: approve, hide, answer, pin or unpin.
+func ModerateAudienceQuestion(questionID primitive.ObjectID, action string) (*models.AudienceQuestion, error) {
+	nowTime, _ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339));
+	set := bson.M{"updated_at": nowTime};
+
+	switch action {
+	case "approve":
+		set["status"] = utils.QNA_APPROVED;
+	case "hide":
+		set["status"] = utils.QNA_HIDDEN;
+		set["pinned"] = false;
+	case "answer":
+		set["status"] = utils.QNA_ANSWERED;
+		set["pinned"] = false;
+		set["answered_at"] = nowTime;
+	case "pin":
+		set["pinned"] = true;
+	case "unpin":
+		set["pinned"] = false;
+	default:
+		return nil, fmt.Errorf("unknown moderation action: %s", action);
+	}
+
+	mongoDb := database.GetMongoInstance();
+	qnaCollection := mongoDb.GetCollection(utils.QNA_COLLECTION);
+	ctx := context.Background();
+
+	var question models.AudienceQuestion;
+	err := qnaCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": questionID},
+		bson.M{"$set": set},
+	).Decode(&question);
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("audience question not found");
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate audience question: %v", err);
+	}
+
+	log.Printf("Audience question %s moderated: %s", questionID.Hex(), action)
+	return &question, nil;
+}
+
+// RankAudienceQuestions sorts pinned questions first, then open questions by hot score,
+// and answered questions last. The hot score favours upvotes but decays with age so new
+// questions get a chance to surface.
+func RankAudienceQuestions(questions []models.AudienceQuestion, now time.Time) {
+	for i := range questions {
+		ageHours := now.Sub(questions[i].CreatedAt).Hours();
+		if ageHours < 0 {
+			ageHours = 0;
+		}
+		questions[i].Score = float64(questions[i].Upvotes+1) / math.Pow(ageHours+2, 1.5);
+	}
+
+	sort.SliceStable(questions, func(a, b int) bool {
+		qa, qb := questions[a], questions[b];
+		if qa.Pinned != qb.Pinned {
+			return qa.Pinned;
+		}
+
+		answeredA, answeredB := qa.Status == utils.QNA_ANSWERED, qb.Status == utils.QNA_ANSWERED;
+		if answeredA != answeredB {
+			return answeredB;
+		}
+
+		if qa.Score != qb.Score {
+			return qa.Score > qb.Score;
+		}
+		return qa.CreatedAt.Before(qb.CreatedAt);
+	})
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AddQuizScore adds the score of an answer to the participant's total in the session leaderboard.
+// A zero score still registers the participant so they get a rank.
+func AddQuizScore(sessionID primitive.ObjectID, participantID string, score int) error {
+	redisDb := database.GetRedisInstance();
+	ctx := context.Background();
+
+	leaderboardKey := utils.QUIZ_LEADERBOARD_KEY_PREFIX + sessionID.Hex();
+	err := redisDb.GetClient().ZIncrBy(ctx, leaderboardKey, float64(score), leaderboardMember(participantID)).Err();
+	if err != nil {
+		return fmt.Errorf("failed to update leaderboard: %v", err);
+	}
+
+	redisDb.GetClient().Expire(ctx, leaderboardKey, SessionCacheTTL);
+	return nil;
+}
+
+// GetLeaderboard returns the top `limit` participants of a quiz session (limit <= 0 means everyone).
+func GetLeaderboard(sessionID primitive.ObjectID, limit int) (*models.Leaderboard, error) {
+	redisDb := database.GetRedisInstance();
+	ctx := context.Background();
+
+	leaderboardKey := utils.QUIZ_LEADERBOARD_KEY_PREFIX + sessionID.Hex();
+	total, err := redisDb.GetClient().ZCard(ctx, leaderboardKey).Result();
+	if err != nil || total == 0 {
+		log.Printf("Leaderboard for session %s not in Redis, rebuilding from MongoDB", sessionID.Hex())
+		if err := rebuildLeaderboardFromMongo(sessionID); err != nil {
+			return nil, err;
+		}
+		total, _ = redisDb.GetClient().ZCard(ctx, leaderboardKey).Result();
+	}
+
+	stop := int64(limit) - 1;
+	if limit <= 0 {
+		stop = -1;
+	}
+
+	scores, err := redisDb.GetClient().ZRevRangeWithScores(ctx, leaderboardKey, 0, stop).Result();
+	if err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard: %v", err);
+	}
+
+	entries := make([]models.LeaderboardEntry, 0, len(scores));
+	for i, z := range scores {
+		entries = append(entries, models.LeaderboardEntry{
+			Rank: i + 1,
+			ParticipantID: z.Member.(string),
+			Score: int(z.Score),
+		})
+	}
+
+	return &models.Leaderboard{
+		SessionID: sessionID.Hex(),
+		Entries: entries,
+		TotalParticipants: int(total),
+	}, nil;
+}
+
+// GetParticipantStanding returns the 1-based rank and score of a participant, or nil if they have not answered yet.
+func GetParticipantStanding(sessionID primitive.ObjectID, participantID string) (*models.LeaderboardEntry, error) {
+	redisDb := database.GetRedisInstance();
+	ctx := context.Background();
+
+	leaderboardKey := utils.QUIZ_LEADERBOARD_KEY_PREFIX + sessionID.Hex();
+	participantID = leaderboardMember(participantID);
+	rank, err := redisDb.GetClient().ZRevRank(ctx, leaderboardKey, participantID).Result();
+	if err == redis.Nil {
+		return nil, nil;
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participant rank: %v", err);
+	}
+
+	score, err := redisDb.GetClient().ZScore(ctx, leaderboardKey, participantID).Result();
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participant score: %v", err);
+	}
+
+	return &models.LeaderboardEntry{
+		Rank: int(rank) + 1,
+		ParticipantID: participantID,
+		Score: int(score),
+	}, nil;
+}
+
+// rebuildLeaderboardFromMongo sums the stored vote scores when the redis leaderboard expired.
+// The totals go into a temporary key that replaces the leaderboard at once, so scores added
+// meanwhile are not counted twice.
+func rebuildLeaderboardFromMongo(sessionID primitive.ObjectID) error {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	redisDb := database.GetRedisInstance();
+	ctx := context.Background();
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"session_id": sessionID}},
+		{"$group": bson.M{
+			"_id": "$participant_id",
+			"score": bson.M{"$sum": "$score"},
+		}},
+	}
+
+	cursor, err := votesCollection.Aggregate(ctx, pipeline);
+	if err != nil {
+		return fmt.Errorf("leaderboard aggregation failed: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	var result struct {
+		ParticipantID primitive.ObjectID `bson:"_id"`
+		Score int `bson:"score"`
+	}
+
+	members := []redis.Z{};
+	for cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			continue;
+		}
+		members = append(members, redis.Z{Score: float64(result.Score), Member: leaderboardMember(result.ParticipantID.Hex())});
+	}
+	if len(members) == 0 {
+		return nil;
+	}
+
+	leaderboardKey := utils.QUIZ_LEADERBOARD_KEY_PREFIX + sessionID.Hex();
+	rebuildKey := leaderboardKey + ":rebuild:" + primitive.NewObjectID().Hex();
+	if err := redisDb.GetClient().ZAdd(ctx, rebuildKey, members...).Err(); err != nil {
+		return fmt.Errorf("failed to rebuild leaderboard: %v", err);
+	}
+	if err := redisDb.GetClient().Rename(ctx, rebuildKey, leaderboardKey).Err(); err != nil {
+		redisDb.GetClient().Del(ctx, rebuildKey);
+		return fmt.Errorf("failed to rebuild leaderboard: %v", err);
+	}
+
+	redisDb.GetClient().Expire(ctx, leaderboardKey, SessionCacheTTL);
+	return nil;
+}
+
+// leaderboardMember is the sorted set member of a participant: the canonical hex of the
+// participant id, the same form the votes store it in.
+func leaderboardMember(participantID string) string {
+	if objectID, err := primitive.ObjectIDFromHex(participantID); err == nil {
+		return objectID.Hex();
+	}
+	return participantID;
+}
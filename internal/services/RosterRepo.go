@@ -0,0 +1,274 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrRosterLocked = errors.New("roster cannot be replaced once voting has started");
+
+// ReplaceRoster swaps the eligible-voter roster of a session for the given entries, in one transaction.
+// Voters are matched on their external id, or their email when they have none, and keep their
+// entry id across uploads; voters missing from the new roster are removed. The ids and creation
+// times of the saved entries are written back into entries.
+func ReplaceRoster(sessionID primitive.ObjectID, entries []models.RosterEntry) error {
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	err := mongoDb.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) error {
+		// votes and turnout point at entry ids, the roster is frozen once the first one is cast.
+		votes, err := votesCollection.CountDocuments(sessionCtx, bson.M{"session_id": sessionID}, options.Count().SetLimit(1));
+		if err != nil {
+			return err;
+		}
+		if votes > 0 {
+			return ErrRosterLocked;
+		}
+
+		kept := make([]primitive.ObjectID, len(entries));
+		for i := range entries {
+			filter := bson.M{"session_id": sessionID, "email": entries[i].Email};
+			if entries[i].ExternalID != "" {
+				filter = bson.M{"session_id": sessionID, "external_id": entries[i].ExternalID};
+			}
+
+			update := bson.M{
+				"$set": bson.M{
+					"email": entries[i].Email,
+					"external_id": entries[i].ExternalID,
+					"name": entries[i].Name,
+					"weight": entries[i].Weight,
+					"invite_token_hash": entries[i].InviteTokenHash,
+				},
+				"$unset": bson.M{"invite_used_at": "", "voter_token_hash": ""},
+				"$setOnInsert": bson.M{
+					"_id": primitive.NewObjectID(),
+					"session_id": sessionID,
+					"created_at": entries[i].CreatedAt,
+				},
+			};
+
+			err := rosterCollection.FindOneAndUpdate(sessionCtx, filter, update,
+				options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+			).Decode(&entries[i]);
+			if err != nil {
+				return err;
+			}
+			kept[i] = entries[i].ID;
+		}
+
+		_, err = rosterCollection.DeleteMany(sessionCtx, bson.M{"session_id": sessionID, "_id": bson.M{"$nin": kept}});
+		return err;
+	});
+	if err == ErrRosterLocked {
+		return err;
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save roster: %v", err);
+	}
+
+	log.Printf("Roster of session %s replaced with %d entries", sessionID.Hex(), len(entries))
+	return nil;
+}
+
+func GetRoster(sessionID primitive.ObjectID) ([]models.RosterEntry, error) {
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	ctx := context.Background();
+
+	cursor, err := rosterCollection.Find(ctx, bson.M{"session_id": sessionID});
+	if err != nil {
+		return nil, fmt.Errorf("failed to find roster: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	entries := []models.RosterEntry{};
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode roster: %v", err);
+	}
+	return entries, nil;
+}
+
+// GetRosterEntry looks up the roster entry a participant votes as.
+func GetRosterEntry(sessionID primitive.ObjectID, participantID string) (*models.RosterEntry, error) {
+	entryID, err := primitive.ObjectIDFromHex(participantID);
+	if err != nil {
+		return nil, fmt.Errorf("participant not on roster");
+	}
+
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	ctx := context.Background();
+
+	var entry models.RosterEntry;
+	err = rosterCollection.FindOne(ctx, bson.M{"_id": entryID, "session_id": sessionID}).Decode(&entry);
+	if err != nil {
+		return nil, fmt.Errorf("participant not on roster");
+	}
+	return &entry, nil;
+}
+
+// GetRosterTotals returns how many voters are eligible and their combined weight.
+func GetRosterTotals(sessionID primitive.ObjectID) (int, float64, error) {
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	ctx := context.Background();
+
+	cursor, err := rosterCollection.Aggregate(ctx, []bson.M{
+		{"$match": bson.M{"session_id": sessionID}},
+		{"$group": bson.M{
+			"_id": nil,
+			"count": bson.M{"$sum": 1},
+			"weight": bson.M{"$sum": "$weight"},
+		}},
+	});
+	if err != nil {
+		return 0, 0, fmt.Errorf("roster aggregation failed: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	var totals struct {
+		Count int `bson:"count"`
+		Weight float64 `bson:"weight"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&totals); err != nil {
+			return 0, 0, fmt.Errorf("failed to decode roster totals: %v", err);
+		}
+	}
+	return totals.Count, totals.Weight, nil;
+}
+
+// RedeemInviteToken uses up an invite token: the voter it was issued to gets the voter token
+// (stored as its digest) their votes are cast with from then on. A token can be redeemed once.
+func RedeemInviteToken(sessionID primitive.ObjectID, token, voterTokenHash string) (*models.RosterEntry, error) {
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	ctx := context.Background();
+
+	tokenHash := utils.HashToken(token);
+
+	var entry models.RosterEntry;
+	err := rosterCollection.FindOneAndUpdate(ctx,
+		bson.M{"session_id": sessionID, "invite_token_hash": tokenHash, "invite_used_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"invite_used_at": time.Now(), "voter_token_hash": voterTokenHash}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&entry);
+	if err == mongo.ErrNoDocuments {
+		if used, _ := rosterCollection.CountDocuments(ctx, bson.M{"session_id": sessionID, "invite_token_hash": tokenHash}); used > 0 {
+			return nil, fmt.Errorf("invite token already used");
+		}
+		return nil, fmt.Errorf("invalid invite token");
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeem invite token: %v", err);
+	}
+	return &entry, nil;
+}
+
+// GetRosterEntryByVoterToken resolves a voter token to the voter who redeemed their invite for it.
+func GetRosterEntryByVoterToken(sessionID primitive.ObjectID, token string) (*models.RosterEntry, error) {
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	ctx := context.Background();
+
+	var entry models.RosterEntry;
+	err := rosterCollection.FindOne(ctx, bson.M{
+		"session_id": sessionID,
+		"voter_token_hash": utils.HashToken(token),
+	}).Decode(&entry);
+	if err != nil {
+		return nil, fmt.Errorf("invalid voter token");
+	}
+	return &entry, nil;
+}
+
+// SetInviteTokenHash stores the digest of a newly issued invite token, invalidating the previous one
+// and the voter token it may have been redeemed for.
+func SetInviteTokenHash(sessionID, entryID primitive.ObjectID, tokenHash string) error {
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	ctx := context.Background();
+
+	result, err := rosterCollection.UpdateOne(ctx,
+		bson.M{"_id": entryID, "session_id": sessionID},
+		bson.M{
+			"$set": bson.M{"invite_token_hash": tokenHash},
+			"$unset": bson.M{"invite_used_at": "", "voter_token_hash": ""},
+		},
+	);
+	if err != nil {
+		return fmt.Errorf("failed to store invite token: %v", err);
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("roster entry not found");
+	}
+	return nil;
+}
+
+// MarkRosterVoted records the first time a roster voter cast a vote.
+func MarkRosterVoted(sessionID primitive.ObjectID, participantID string) error {
+	entryID, err := primitive.ObjectIDFromHex(participantID);
+	if err != nil {
+		return nil;
+	}
+
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	ctx := context.Background();
+
+	_, err = rosterCollection.UpdateOne(ctx,
+		bson.M{"_id": entryID, "session_id": sessionID, "voted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"voted_at": time.Now()}},
+	);
+	if err != nil {
+		return fmt.Errorf("failed to mark roster voter: %v", err);
+	}
+	return nil;
+}
+
+// GetRosterVotedTotals returns how many roster voters have voted and their combined weight.
+func GetRosterVotedTotals(sessionID primitive.ObjectID) (int, float64, error) {
+	mongoDb := database.GetMongoInstance();
+	rosterCollection := mongoDb.GetCollection(utils.ROSTER_COLLECTION);
+	ctx := context.Background();
+
+	cursor, err := rosterCollection.Aggregate(ctx, []bson.M{
+		{"$match": bson.M{"session_id": sessionID, "voted_at": bson.M{"$exists": true}}},
+		{"$group": bson.M{
+			"_id": nil,
+			"count": bson.M{"$sum": 1},
+			"weight": bson.M{"$sum": "$weight"},
+		}},
+	});
+	if err != nil {
+		return 0, 0, fmt.Errorf("turnout aggregation failed: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	var totals struct {
+		Count int `bson:"count"`
+		Weight float64 `bson:"weight"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&totals); err != nil {
+			return 0, 0, fmt.Errorf("failed to decode turnout totals: %v", err);
+		}
+	}
+	return totals.Count, totals.Weight, nil;
+}
+
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetSlotAnswers returns the current availability answers of a schedule question, with the participant
+// (absent for secret ballots) and no selection data.
+func GetSlotAnswers(sessionID, questionID primitive.ObjectID) ([]models.Vote, error) {
+	mongoDb := database.GetMongoInstance();
+	votesCollection := mongoDb.GetCollection(utils.VOTES_COLLECTION);
+	ctx := context.Background();
+
+	cursor, err := votesCollection.Find(ctx,
+		bson.M{
+			"session_id":   sessionID,
+			"question_id":  questionID,
+			"retracted":    bson.M{"$ne": true},
+			"availability": bson.M{"$exists": true},
+		},
+		options.Find().SetProjection(bson.M{"participant_id": 1, "availability": 1, "created_at": 1}).SetSort(bson.M{"created_at": 1}),
+	);
+	if err != nil {
+		return nil, fmt.Errorf("failed to load slot answers: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	answers := []models.Vote{};
+	if err := cursor.All(ctx, &answers); err != nil {
+		return nil, fmt.Errorf("failed to decode slot answers: %v", err);
+	}
+	return answers, nil;
+}
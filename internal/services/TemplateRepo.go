@@ -0,0 +1,164 @@
+package services
+
+import (
+	"RealTimePoll/internal/database"
+	"RealTimePoll/internal/models"
+	"RealTimePoll/internal/utils"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrTemplateNotFound = errors.New("template not found");
+var ErrBankQuestionNotFound = errors.New("bank question not found");
+
+func SaveTemplate(template models.SessionTemplate) error {
+	mongoDb := database.GetMongoInstance();
+	templatesCollection := mongoDb.GetCollection(utils.TEMPLATES_COLLECTION);
+
+	if _, err := templatesCollection.InsertOne(context.Background(), template); err != nil {
+		return fmt.Errorf("failed to save template: %v", err);
+	}
+
+	log.Printf("Template %s saved for organizer %s", template.ID.Hex(), template.OrganizerId.Hex())
+	return nil;
+}
+
+// ListTemplates returns the organizer's templates, most recent first.
+func ListTemplates(organizerID primitive.ObjectID) ([]models.SessionTemplate, error) {
+	mongoDb := database.GetMongoInstance();
+	templatesCollection := mongoDb.GetCollection(utils.TEMPLATES_COLLECTION);
+	ctx := context.Background();
+
+	cursor, err := templatesCollection.Find(ctx, bson.M{"organizer_id": organizerID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}));
+	if err != nil {
+		return nil, fmt.Errorf("failed to find templates: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	templates := []models.SessionTemplate{};
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode templates: %v", err);
+	}
+	return templates, nil;
+}
+
+// GetTemplate returns a template of the organizer, ErrTemplateNotFound for others' templates.
+func GetTemplate(templateID, organizerID primitive.ObjectID) (*models.SessionTemplate, error) {
+	mongoDb := database.GetMongoInstance();
+	templatesCollection := mongoDb.GetCollection(utils.TEMPLATES_COLLECTION);
+
+	var template models.SessionTemplate;
+	err := templatesCollection.FindOne(context.Background(), bson.M{"_id": templateID, "organizer_id": organizerID}).Decode(&template);
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrTemplateNotFound;
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find template: %v", err);
+	}
+	return &template, nil;
+}
+
+func DeleteTemplate(templateID, organizerID primitive.ObjectID) error {
+	mongoDb := database.GetMongoInstance();
+	templatesCollection := mongoDb.GetCollection(utils.TEMPLATES_COLLECTION);
+
+	result, err := templatesCollection.DeleteOne(context.Background(), bson.M{"_id": templateID, "organizer_id": organizerID});
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %v", err);
+	}
+	if result.DeletedCount == 0 {
+		return ErrTemplateNotFound;
+	}
+	return nil;
+}
+
+func SaveBankQuestion(question models.BankQuestion) error {
+	mongoDb := database.GetMongoInstance();
+	bankCollection := mongoDb.GetCollection(utils.QUESTION_BANK_COLLECTION);
+
+	if _, err := bankCollection.InsertOne(context.Background(), question); err != nil {
+		return fmt.Errorf("failed to save bank question: %v", err);
+	}
+	return nil;
+}
+
+// ListBankQuestions returns the organizer's bank questions carrying every one of the tags, most recent first.
+func ListBankQuestions(organizerID primitive.ObjectID, tags []string) ([]models.BankQuestion, error) {
+	mongoDb := database.GetMongoInstance();
+	bankCollection := mongoDb.GetCollection(utils.QUESTION_BANK_COLLECTION);
+	ctx := context.Background();
+
+	filter := bson.M{"organizer_id": organizerID};
+	if len(tags) > 0 {
+		filter["tags"] = bson.M{"$all": tags};
+	}
+
+	cursor, err := bankCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}));
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bank questions: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	questions := []models.BankQuestion{};
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, fmt.Errorf("failed to decode bank questions: %v", err);
+	}
+	return questions, nil;
+}
+
+// GetBankQuestions returns the organizer's bank questions in the order of the ids.
+// It fails with ErrBankQuestionNotFound when one of them is missing.
+func GetBankQuestions(organizerID primitive.ObjectID, ids []primitive.ObjectID) ([]models.BankQuestion, error) {
+	mongoDb := database.GetMongoInstance();
+	bankCollection := mongoDb.GetCollection(utils.QUESTION_BANK_COLLECTION);
+	ctx := context.Background();
+
+	cursor, err := bankCollection.Find(ctx, bson.M{"organizer_id": organizerID, "_id": bson.M{"$in": ids}});
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bank questions: %v", err);
+	}
+	defer cursor.Close(ctx);
+
+	found := []models.BankQuestion{};
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, fmt.Errorf("failed to decode bank questions: %v", err);
+	}
+
+	byID := make(map[primitive.ObjectID]models.BankQuestion, len(found));
+	for _, question := range found {
+		byID[question.ID] = question;
+	}
+
+	questions := make([]models.BankQuestion, len(ids));
+	for i, id := range ids {
+		question, exists := byID[id];
+		if !exists {
+			return nil, ErrBankQuestionNotFound;
+		}
+		questions[i] = question;
+	}
+	return questions, nil;
+}
+
+func DeleteBankQuestion(questionID, organizerID primitive.ObjectID) error {
+	mongoDb := database.GetMongoInstance();
+	bankCollection := mongoDb.GetCollection(utils.QUESTION_BANK_COLLECTION);
+
+	result, err := bankCollection.DeleteOne(context.Background(), bson.M{"_id": questionID, "organizer_id": organizerID});
+	if err != nil {
+		return fmt.Errorf("failed to delete bank question: %v", err);
+	}
+	if result.DeletedCount == 0 {
+		return ErrBankQuestionNotFound;
+	}
+	return nil;
+}
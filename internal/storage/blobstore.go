@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned by Get when no blob is stored under the key.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore keeps uploaded files. Keys are slash separated paths such as "images/<id>.png".
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, string, error) // content and content type
+	URL(key string) string // where clients download the blob from
+}
+
+var (
+	defaultStore BlobStore
+	loadStoreOnce sync.Once
+)
+
+// Default returns the store selected by BLOB_BACKEND: "local" (default, files under BLOB_DIR) or
+// "s3" for an S3-compatible bucket configured with the S3_* variables.
+func Default() BlobStore {
+	loadStoreOnce.Do(func() {
+		switch os.Getenv("BLOB_BACKEND") {
+		case "s3":
+			store, err := NewS3Store(S3Config{
+				Endpoint:  os.Getenv("S3_ENDPOINT"),
+				Region:    envOr("S3_REGION", "us-east-1"),
+				Bucket:    os.Getenv("S3_BUCKET"),
+				AccessKey: os.Getenv("S3_ACCESS_KEY"),
+				SecretKey: os.Getenv("S3_SECRET_KEY"),
+				PublicURL: os.Getenv("S3_PUBLIC_URL"),
+			})
+			if err == nil {
+				defaultStore = store
+				return
+			}
+			log.Printf("S3 blob store unavailable (%v), falling back to local storage", err)
+		}
+		defaultStore = NewLocalStore(envOr("BLOB_DIR", "./uploads"), envOr("BLOB_PUBLIC_URL", "http://localhost:8080/api/v1/blobs"))
+	})
+	return defaultStore
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// validKey rejects keys that could leave the store's namespace.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decoders register themselves with image.Decode
+	_ "image/jpeg"
+	"image/png"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"RealTimePoll/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MaxImageBytes  = 5 << 20 // upload size limit
+	maxImagePixels = 40_000_000 // guards against decompression bombs
+	thumbnailSize  = 256 // longest side of a thumbnail, in pixels
+)
+
+// image ids are "<objectId>.<ext>", the extension giving the stored format.
+var imageIDPattern = regexp.MustCompile(`^[0-9a-f]{24}\.(png|jpg|gif)$`)
+
+var imageExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+}
+
+// StoredImage describes an uploaded image and its thumbnail.
+type StoredImage struct {
+	ID           string `json:"id"`
+	ContentType  string `json:"contentType"`
+	Size         int    `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+}
+
+// SaveImage checks that data is a PNG, JPEG or GIF image from its content (the declared type is not
+// trusted), stores it and a PNG thumbnail, and returns where both can be downloaded.
+func SaveImage(ctx context.Context, store BlobStore, data []byte) (*StoredImage, error) {
+	if len(data) > MaxImageBytes {
+		return nil, fmt.Errorf("image is larger than %d MB", MaxImageBytes>>20)
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported image type %s, use PNG, JPEG or GIF", contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are too large", config.Width, config.Height)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+
+	var thumbnail bytes.Buffer
+	if err := png.Encode(&thumbnail, Thumbnail(decoded, thumbnailSize)); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %v", err)
+	}
+
+	id := primitive.NewObjectID().Hex() + "." + extension
+	if err := store.Put(ctx, ImageKey(id), contentType, data); err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, ThumbnailKey(id), "image/png", thumbnail.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return &StoredImage{
+		ID:           id,
+		ContentType:  contentType,
+		Size:         len(data),
+		Width:        config.Width,
+		Height:       config.Height,
+		URL:          store.URL(ImageKey(id)),
+		ThumbnailURL: store.URL(ThumbnailKey(id)),
+	}, nil
+}
+
+func ValidImageID(id string) bool {
+	return imageIDPattern.MatchString(id)
+}
+
+func ImageKey(id string) string {
+	return "images/" + id
+}
+
+func ThumbnailKey(id string) string {
+	return "thumbnails/" + strings.TrimSuffix(id, id[strings.LastIndex(id, "."):]) + ".png"
+}
+
+// Thumbnail scales an image down so its longest side is at most size, averaging the source pixels
+// each target pixel covers. Smaller images are returned as they are.
+func Thumbnail(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return src
+	}
+
+	targetWidth, targetHeight := size, height*size/width
+	if height > width {
+		targetWidth, targetHeight = width*size/height, size
+	}
+	if targetWidth < 1 {
+		targetWidth = 1
+	}
+	if targetHeight < 1 {
+		targetHeight = 1
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := 0; y < targetHeight; y++ {
+		y0 := bounds.Min.Y + y*height/targetHeight
+		y1 := bounds.Min.Y + (y+1)*height/targetHeight
+		for x := 0; x < targetWidth; x++ {
+			x0 := bounds.Min.X + x*width/targetWidth
+			x1 := bounds.Min.X + (x+1)*width/targetWidth
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pixel := color.NRGBA64Model.Convert(src.At(sx, sy)).(color.NRGBA64)
+					r += uint64(pixel.R)
+					g += uint64(pixel.G)
+					b += uint64(pixel.B)
+					a += uint64(pixel.A)
+					count++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / count >> 8),
+				G: uint8(g / count >> 8),
+				B: uint8(b / count >> 8),
+				A: uint8(a / count >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// ResolveOptionImages returns option images with their download URLs filled in.
+// The given slice is left untouched, it may be shared with a cached session.
+func ResolveOptionImages(store BlobStore, images []models.OptionImage) []models.OptionImage {
+	if len(images) == 0 {
+		return images
+	}
+
+	resolved := make([]models.OptionImage, len(images))
+	for i, optionImage := range images {
+		resolved[i] = models.OptionImage{ID: optionImage.ID}
+		if ValidImageID(optionImage.ID) {
+			resolved[i].URL = store.URL(ImageKey(optionImage.ID))
+			resolved[i].ThumbnailURL = store.URL(ThumbnailKey(optionImage.ID))
+		}
+	}
+	return resolved
+}
@@ -1,5 +1,7 @@
 package utils
 
+import "time"
+
 // session status
 var ACTIVE string = "active"
 var DRAFT string = "draft"
@@ -8,6 +10,50 @@ var CLOSED string = "closed"
 // question types
 var SINGLE string = "single"
 var MULTIPLE string = "multiple"
+var SCHEDULE string = "schedule" // options are time slots, answered with an availability per slot
+var ALLOCATION string = "allocation" // participants spread a budget of points across the options
+var MATRIX string = "matrix" // rows answered on one shared scale, the options are the columns
+var PULSE string = "pulse" // live slider moved at any time over the websocket, sampled over time
+
+// availability answers of schedule questions
+var AVAILABILITY_YES string = "yes"
+var AVAILABILITY_IF_NEED_BE string = "if_need_be"
+var AVAILABILITY_NO string = "no"
+
+// audience question (Q&A) status
+var QNA_PENDING string = "pending"
+var QNA_APPROVED string = "approved"
+var QNA_HIDDEN string = "hidden"
+var QNA_ANSWERED string = "answered"
+var QNA_MAX_TEXT_LENGTH int = 500
+var OTHER_TEXT_MAX_LENGTH int = 200 // free text of "Other (please specify)" options
+
+// vote policies
+var VOTE_LOCKED string = "locked"
+var VOTE_CHANGEABLE string = "changeable"
+var VOTE_RETRACTABLE string = "retractable"
+
+// results visibility policies
+var RESULTS_ALWAYS string = "always"
+var RESULTS_AFTER_VOTE string = "after_vote"
+var RESULTS_AFTER_CLOSE string = "after_close"
+var RESULTS_ORGANIZER_ONLY string = "organizer_only"
+
+// session modes
+var POLL_MODE string = "poll"
+var QUIZ_MODE string = "quiz"
+
+// quiz scoring
+var QUIZ_DEFAULT_POINTS int = 1000
+var QUIZ_DEFAULT_TIME_LIMIT_SECONDS int = 30
+var QUIZ_LEADERBOARD_SIZE int = 10
+
+// secret ballots
+var BALLOT_TIME_GRANULARITY = time.Hour; // ballot timestamps are truncated so they cannot be matched to participations
+var MIN_BALLOT_HASH_KEY_LENGTH = 32;
+
+// vote receipts
+var VOTE_RECEIPT_TTL = 7 * 24 * time.Hour;
 
 // jwt
 var JWT_CLAIM_ISSUER  string = "polling-platform";
@@ -15,6 +61,7 @@ var AUTHORIZATION_HEADER string  = "Authorization";
 
 // redis constants
 var USER_KEY_PREFIX string = "user:email:";
+var QUIZ_LEADERBOARD_KEY_PREFIX string = "quiz_leaderboard:";
 var REDIS_CONNECTION string = "redis://localhost:6379";
 
 // mongo constants
@@ -23,14 +70,30 @@ var SESSION_COLLECTION string = "session_polls";
 var DB_NAME string = "polling";
 var MONGO_CONNECTION string = "mongodb://localhost:27017";
 var VOTES_COLLECTION string = "votes";
+var QNA_COLLECTION string = "audience_questions";
+var ROSTER_COLLECTION string = "rosters";
+var PARTICIPATIONS_COLLECTION string = "participations";
+var TALLY_AUDITS_COLLECTION string = "tally_audits";
+var QUESTION_VIEWS_COLLECTION string = "question_views";
+var PULSE_SAMPLES_COLLECTION string = "pulse_samples";
+var TEMPLATES_COLLECTION string = "session_templates";
+var QUESTION_BANK_COLLECTION string = "question_bank";
 
 // kafka constants
 const (
 	VOTES_SUBMITTED_TOPIC = "votes.submitted"
 	VOTES_PROCESSED_TOPIC = "votes.processed"
 	RESULTS_UPDATED_TOPIC = "votes.updated"
+	LEADERBOARD_UPDATED_TOPIC = "quiz.leaderboard"
+	QNA_UPDATED_TOPIC = "qna.updated"
+	CLOSURES_TOPIC = "sessions.closed"
+	RECEIPTS_TOPIC = "votes.receipts"
 )
 
+// event types sharing the votes.submitted topic
+var SURVEY_SUBMITTED_EVENT string = "survey.submitted"
+var VOTE_RETRACTED_EVENT string = "vote.retracted"
+
 var KAFKA_CONNECTION string = "localhost:29092";
 var KAFKA_GROUP_ID string = "vote-processor-group";
 var KAFKA_RESULTS_BROADCASTER_GROUP string = "results-broadcaster-group";
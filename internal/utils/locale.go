@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BCP 47 style tags as sent by browsers: "en", "de-CH", "zh-Hant-TW".
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`);
+
+func ValidLocale(locale string) bool {
+	return localePattern.MatchString(locale);
+}
+
+// NegotiateLocale picks the available locale to show a participant. The explicitly requested
+// locale wins, then the Accept-Language entries by preference. A request matches an available
+// locale exactly, or by language when only the base ("de" for "de-CH") is the same.
+// It returns "" when nothing matches, meaning the default texts.
+func NegotiateLocale(available []string, requested string, acceptLanguage string) string {
+	if len(available) == 0 {
+		return "";
+	}
+
+	wanted := []string{};
+	if requested != "" {
+		wanted = append(wanted, requested);
+	}
+	wanted = append(wanted, parseAcceptLanguage(acceptLanguage)...);
+
+	for _, locale := range wanted {
+		if match := matchLocale(available, locale); match != "" {
+			return match;
+		}
+	}
+	return "";
+}
+
+func matchLocale(available []string, locale string) string {
+	locale = strings.TrimSpace(locale);
+	if locale == "" || locale == "*" {
+		return "";
+	}
+	for _, candidate := range available {
+		if strings.EqualFold(candidate, locale) {
+			return candidate;
+		}
+	}
+	base := baseLanguage(locale);
+	for _, candidate := range available {
+		if strings.EqualFold(baseLanguage(candidate), base) {
+			return candidate;
+		}
+	}
+	return "";
+}
+
+func baseLanguage(locale string) string {
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		return locale[:i];
+	}
+	return locale;
+}
+
+// parseAcceptLanguage returns the languages of an Accept-Language header, most preferred first.
+// Entries with q=0 are dropped.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		quality float64
+	}
+
+	entries := []weighted{};
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";");
+		locale := strings.TrimSpace(fields[0]);
+		if locale == "" {
+			continue;
+		}
+
+		quality := 1.0;
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param);
+			if value, found := strings.CutPrefix(param, "q="); found {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q;
+				}
+			}
+		}
+		if quality <= 0 {
+			continue;
+		}
+		entries = append(entries, weighted{locale: locale, quality: quality});
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].quality > entries[j].quality;
+	})
+	locales := make([]string, len(entries));
+	for i, entry := range entries {
+		locales[i] = entry.locale;
+	}
+	return locales;
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"strings"
+)
+
+// Permutation returns a deterministic shuffle of 0..n-1. The same seed parts always give the
+// same order, so a participant keeps their order across reconnects.
+func Permutation(n int, seedParts ...string) []int {
+	seed := sha256.Sum256([]byte(strings.Join(seedParts, "|")));
+	rng := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed[:8]))));
+
+	order := make([]int, n);
+	for i := range order {
+		order[i] = i;
+	}
+	rng.Shuffle(n, func(i, j int) {
+		order[i], order[j] = order[j], order[i];
+	})
+	return order;
+}
+
+// OptionOrder returns the options of a question as a participant sees them: order[position] is
+// the canonical option index. With pinLast the last option (e.g. "None of the above") stays last.
+func OptionOrder(count int, pinLast bool, sessionID, questionID, participantID string) []int {
+	if !pinLast || count < 2 {
+		return Permutation(count, sessionID, questionID, participantID);
+	}
+	return append(Permutation(count-1, sessionID, questionID, participantID), count-1);
+}
+
+// ToCanonicalOptions maps displayed option positions back to canonical indices. Positions out of
+// range are kept so that validation still rejects them.
+func ToCanonicalOptions(order []int, displayed []int) []int {
+	canonical := make([]int, len(displayed));
+	for i, position := range displayed {
+		canonical[i] = position;
+		if position >= 0 && position < len(order) {
+			canonical[i] = order[position];
+		}
+	}
+	return canonical;
+}
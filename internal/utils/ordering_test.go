@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func isPermutation(order []int, n int) bool {
+	if len(order) != n {
+		return false;
+	}
+	sorted := append([]int(nil), order...);
+	sort.Ints(sorted);
+	for i, value := range sorted {
+		if value != i {
+			return false;
+		}
+	}
+	return true;
+}
+
+func TestPermutation(t *testing.T) {
+	tests := []struct {
+		name string
+		n int
+		seedParts []string
+	}{
+		{"empty", 0, []string{"s"}},
+		{"single", 1, []string{"s", "q", "p"}},
+		{"small", 4, []string{"s", "q", "p"}},
+		{"large", 50, []string{"session", "question", "participant"}},
+		{"no seed parts", 6, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := Permutation(tt.n, tt.seedParts...);
+			if !isPermutation(order, tt.n) {
+				t.Fatalf("Permutation(%d) = %v, not a permutation", tt.n, order);
+			}
+			if again := Permutation(tt.n, tt.seedParts...); !reflect.DeepEqual(order, again) {
+				t.Errorf("Permutation(%d) not deterministic: %v then %v", tt.n, order, again);
+			}
+		})
+	}
+}
+
+func TestPermutationDependsOnSeed(t *testing.T) {
+	base := Permutation(20, "session", "question", "alice");
+	others := [][]string{
+		{"session", "question", "bob"},
+		{"session", "other-question", "alice"},
+		{"other-session", "question", "alice"},
+		// parts are joined with a separator, so moving text between parts changes the seed.
+		{"session", "questio", "nalice"},
+	};
+	for _, seedParts := range others {
+		if reflect.DeepEqual(base, Permutation(20, seedParts...)) {
+			t.Errorf("seed %v gives the same order as the base seed", seedParts);
+		}
+	}
+}
+
+func TestOptionOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		count int
+		pinLast bool
+	}{
+		{"no options", 0, true},
+		{"one option pinned", 1, true},
+		{"two options pinned", 2, true},
+		{"five options pinned", 5, true},
+		{"five options free", 5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := OptionOrder(tt.count, tt.pinLast, "s", "q", "p");
+			if !isPermutation(order, tt.count) {
+				t.Fatalf("OptionOrder() = %v, not a permutation of %d", order, tt.count);
+			}
+			if tt.pinLast && tt.count > 0 && order[tt.count-1] != tt.count-1 {
+				t.Errorf("OptionOrder() = %v, last option not pinned", order);
+			}
+		})
+	}
+}
+
+func TestOptionOrderPinsLastForEveryParticipant(t *testing.T) {
+	for _, participantID := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		order := OptionOrder(4, true, "s", "q", participantID);
+		if order[3] != 3 {
+			t.Errorf("participant %s: OptionOrder() = %v, last option not pinned", participantID, order);
+		}
+	}
+}
+
+func TestToCanonicalOptions(t *testing.T) {
+	order := []int{2, 0, 3, 1};
+	tests := []struct {
+		name string
+		displayed []int
+		want []int
+	}{
+		{"nothing selected", []int{}, []int{}},
+		{"single position", []int{0}, []int{2}},
+		{"several positions", []int{1, 3}, []int{0, 1}},
+		{"all positions", []int{0, 1, 2, 3}, []int{2, 0, 3, 1}},
+		{"out of range kept", []int{4, -1}, []int{4, -1}},
+		{"mixed", []int{2, 9}, []int{3, 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToCanonicalOptions(order, tt.displayed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToCanonicalOptions(%v) = %v, want %v", tt.displayed, got, tt.want);
+			}
+		})
+	}
+}
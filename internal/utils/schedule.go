@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SlotLocation returns the zone a slot is shown in, UTC when none is given.
+func SlotLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil;
+	}
+	return time.LoadLocation(timezone);
+}
+
+// SlotLabel is the default option text of a time slot, e.g. "Mon 2 Jan 2006 15:04-16:00 CET".
+func SlotLabel(start, end time.Time, timezone string) string {
+	location, err := SlotLocation(timezone);
+	if err != nil {
+		location = time.UTC;
+	}
+	start, end = start.In(location), end.In(location);
+
+	if start.YearDay() == end.YearDay() && start.Year() == end.Year() {
+		return fmt.Sprintf("%s-%s %s", start.Format("Mon 2 Jan 2006 15:04"), end.Format("15:04"), start.Format("MST"));
+	}
+	return fmt.Sprintf("%s - %s", start.Format("Mon 2 Jan 2006 15:04 MST"), end.Format("Mon 2 Jan 2006 15:04 MST"));
+}
+
+// CalendarEvent is a single event of an iCalendar (RFC 5545) file.
+type CalendarEvent struct {
+	UID string
+	Summary string
+	Description string
+	Start time.Time
+	End time.Time
+	Timezone string
+}
+
+// BuildICS renders events as an iCalendar file. Times are written in UTC so no VTIMEZONE
+// component is needed, the slot's zone is kept in X-WR-TIMEZONE for calendar clients.
+func BuildICS(events []CalendarEvent, stamp time.Time) []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//RealTimePoll//Schedule//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	};
+	if len(events) > 0 && events[0].Timezone != "" {
+		lines = append(lines, "X-WR-TIMEZONE:" + events[0].Timezone);
+	}
+
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:" + event.UID,
+			"DTSTAMP:" + icsTime(stamp),
+			"DTSTART:" + icsTime(event.Start),
+			"DTEND:" + icsTime(event.End),
+			"SUMMARY:" + icsEscape(event.Summary),
+		);
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:" + icsEscape(event.Description));
+		}
+		lines = append(lines, "END:VEVENT");
+	}
+	lines = append(lines, "END:VCALENDAR");
+
+	var builder strings.Builder;
+	for _, line := range lines {
+		builder.WriteString(foldICSLine(line));
+		builder.WriteString("\r\n");
+	}
+	return []byte(builder.String());
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z");
+}
+
+func icsEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text);
+}
+
+// foldICSLine splits lines longer than 75 octets, continuation lines start with a space.
+// Multi-byte characters are never split.
+func foldICSLine(line string) string {
+	var builder strings.Builder;
+	width := 0;
+	for _, r := range line {
+		size := len(string(r));
+		if width + size > 75 {
+			builder.WriteString("\r\n ");
+			width = 1;
+		}
+		builder.WriteRune(r);
+		width += size;
+	}
+	return builder.String();
+}
@@ -1,10 +1,20 @@
 package utils
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/json"
 	"encoding/hex"
+	"encoding/base64"
+	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"strings"
+	"unicode"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -26,6 +36,100 @@ func GenerateJoinCode() string {
 }
 
 
+// generates a random invite token for a roster voter.
+func GenerateInviteToken() string {
+	bytes := make([]byte, 16);
+	rand.Read(bytes);
+	return hex.EncodeToString(bytes);
+}
+
+// hashes a token so only its digest needs to be stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token));
+	return hex.EncodeToString(sum[:]);
+}
+
+
+var ballotHashKey []byte;
+
+// LoadBallotHashKey reads BALLOT_HASH_KEY, the secret of the keyed hashes and sealed ballots of
+// secret ballot sessions. There is no default: anyone who knows it could recompute the hashes.
+func LoadBallotHashKey() error {
+	key := os.Getenv("BALLOT_HASH_KEY");
+	if len(key) < MIN_BALLOT_HASH_KEY_LENGTH {
+		return fmt.Errorf("BALLOT_HASH_KEY must be set to at least %d characters", MIN_BALLOT_HASH_KEY_LENGTH);
+	}
+	ballotHashKey = []byte(key);
+	return nil;
+}
+
+// BallotHashKey is the secret used for keyed hashes of secret ballot participants.
+func BallotHashKey() []byte {
+	if ballotHashKey == nil {
+		log.Fatal("BALLOT_HASH_KEY not loaded, call LoadBallotHashKey at startup");
+	}
+	return ballotHashKey;
+}
+
+// KeyedHash returns the hex HMAC-SHA256 of the parts, so identifiers can be compared without storing them.
+func KeyedHash(key []byte, parts ...string) string {
+	mac := hmac.New(sha256.New, key);
+	for _, part := range parts {
+		mac.Write([]byte(part));
+		mac.Write([]byte{0});
+	}
+	return hex.EncodeToString(mac.Sum(nil));
+}
+
+// ParticipantToken proves a participant id was handed out by the server for the session, so
+// connections can be bound to it without storing the tokens.
+func ParticipantToken(sessionID, participantID string) string {
+	return KeyedHash(BallotHashKey(), "participant", sessionID, participantID);
+}
+
+// ValidParticipantToken reports whether token is the participant token of the participant id.
+func ValidParticipantToken(sessionID, participantID, token string) bool {
+	return hmac.Equal([]byte(token), []byte(ParticipantToken(sessionID, participantID)));
+}
+
+// SealWithKey encrypts and authenticates plaintext with AES-256-GCM under a key derived from the
+// secret. The result is base64, nonce first.
+func SealWithKey(secret, plaintext []byte) (string, error) {
+	aead, err := sealingCipher(secret);
+	if err != nil {
+		return "", err;
+	}
+
+	nonce := make([]byte, aead.NonceSize());
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err;
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil;
+}
+
+// OpenWithKey reverses SealWithKey.
+func OpenWithKey(secret []byte, sealed string) ([]byte, error) {
+	aead, err := sealingCipher(secret);
+	if err != nil {
+		return nil, err;
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed);
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed sealed data");
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil);
+}
+
+func sealingCipher(secret []byte) (cipher.AEAD, error) {
+	key := sha256.Sum256(append([]byte("seal:"), secret...));
+	block, err := aes.NewCipher(key[:]);
+	if err != nil {
+		return nil, err;
+	}
+	return cipher.NewGCM(block);
+}
+
 func JSONResponse(w http.ResponseWriter,  status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json");
 	w.WriteHeader(status);
@@ -34,4 +138,12 @@ func JSONResponse(w http.ResponseWriter,  status int, data interface{}) {
 
 func ErrorResponse(w http.ResponseWriter, status int , message string) {
 	JSONResponse(w, status, map[string]string{"error":message});
-}
\ No newline at end of file
+}
+// NormalizeFreeText folds free-text answers so variants of the same answer group together:
+// lower case, single spaces and no surrounding punctuation.
+func NormalizeFreeText(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ");
+	return strings.TrimFunc(normalized, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r);
+	});
+}
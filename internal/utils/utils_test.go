@@ -0,0 +1,66 @@
+package utils
+
+import "testing"
+
+func TestNormalizeFreeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"empty", "", ""},
+		{"only spaces", "   \t\n ", ""},
+		{"lower case", "Pizza", "pizza"},
+		{"inner spaces collapsed", "New   York\tCity", "new york city"},
+		{"outer spaces trimmed", "  tacos  ", "tacos"},
+		{"trailing punctuation", "Tacos!!", "tacos"},
+		{"leading punctuation", "...sushi", "sushi"},
+		{"punctuation and spaces mixed", " - ramen ? ", "ramen"},
+		{"inner punctuation kept", "Rock'n'roll, baby", "rock'n'roll, baby"},
+		{"only punctuation", "?!.", ""},
+		{"unicode letters", "  CRÈME Brûlée. ", "crème brûlée"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeFreeText(tt.text); got != tt.want {
+				t.Errorf("NormalizeFreeText(%q) = %q, want %q", tt.text, got, tt.want);
+			}
+		})
+	}
+}
+
+func TestNormalizeFreeTextGroupsVariants(t *testing.T) {
+	variants := []string{"Blue", "blue ", " BLUE!", "blue."};
+	for _, variant := range variants {
+		if got := NormalizeFreeText(variant); got != "blue" {
+			t.Errorf("NormalizeFreeText(%q) = %q, want %q", variant, got, "blue");
+		}
+	}
+}
+
+func TestValidParticipantToken(t *testing.T) {
+	ballotHashKey = []byte("test-ballot-hash-key-of-enough-length");
+	defer func() { ballotHashKey = nil }();
+
+	token := ParticipantToken("session-1", "participant-1");
+	tests := []struct {
+		name          string
+		sessionID     string
+		participantID string
+		token         string
+		want          bool
+	}{
+		{"issued token", "session-1", "participant-1", token, true},
+		{"other participant", "session-1", "participant-2", token, false},
+		{"other session", "session-2", "participant-1", token, false},
+		{"empty token", "session-1", "participant-1", "", false},
+		{"tampered token", "session-1", "participant-1", token[:len(token)-1] + "x", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidParticipantToken(tt.sessionID, tt.participantID, tt.token); got != tt.want {
+				t.Errorf("ValidParticipantToken(%q, %q, %q) = %v, want %v", tt.sessionID, tt.participantID, tt.token, got, tt.want);
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// leaf and node hashes are prefixed differently so an inner node can never pass as a leaf.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// one sibling on the path from a leaf to the root. Left tells on which side the sibling sits.
+type ProofStep struct {
+	Hash string `json:"hash"`
+	Left bool `json:"left"`
+}
+
+func HashLeaf(data []byte) []byte {
+	hash := sha256.Sum256(append([]byte{leafPrefix}, data...))
+	return hash[:]
+}
+
+func hashNode(left, right []byte) []byte {
+	buf := make([]byte, 0, 1+len(left)+len(right))
+	buf = append(buf, nodePrefix)
+	buf = append(buf, left...)
+	buf = append(buf, right...)
+	hash := sha256.Sum256(buf)
+	return hash[:]
+}
+
+// levels builds the tree bottom-up. A node without a sibling is carried to the next level unchanged.
+func levels(leaves [][]byte) [][][]byte {
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = HashLeaf(leaf)
+	}
+
+	tree := [][][]byte{level}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hashNode(level[i], level[i+1]))
+		}
+		tree = append(tree, next)
+		level = next
+	}
+	return tree
+}
+
+// Root returns the merkle root of the leaves, nil when there are none.
+func Root(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return nil
+	}
+	tree := levels(leaves)
+	return tree[len(tree)-1][0]
+}
+
+// Proof returns the inclusion proof of the leaf at index.
+func Proof(leaves [][]byte, index int) ([]ProofStep, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, errors.New("leaf index out of range")
+	}
+
+	proof := []ProofStep{}
+	tree := levels(leaves)
+	for _, level := range tree[:len(tree)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, ProofStep{
+				Hash: hex.EncodeToString(level[sibling]),
+				Left: sibling < index,
+			})
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// Verify recomputes the root from a leaf and its proof.
+func Verify(leaf []byte, proof []ProofStep, root []byte) bool {
+	hash := HashLeaf(leaf)
+	for _, step := range proof {
+		sibling, err := hex.DecodeString(step.Hash)
+		if err != nil {
+			return false
+		}
+		if step.Left {
+			hash = hashNode(sibling, hash)
+		} else {
+			hash = hashNode(hash, sibling)
+		}
+	}
+	return bytes.Equal(hash, root)
+}
@@ -0,0 +1,110 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprintf("ballot-%d", i))
+	}
+	return leaves
+}
+
+func TestRoot(t *testing.T) {
+	a, b, c := []byte("a"), []byte("b"), []byte("c")
+	tests := []struct {
+		name   string
+		leaves [][]byte
+		want   []byte
+	}{
+		{"no leaves", nil, nil},
+		{"single leaf", [][]byte{a}, HashLeaf(a)},
+		{"two leaves", [][]byte{a, b}, hashNode(HashLeaf(a), HashLeaf(b))},
+		{"odd leaf carried up", [][]byte{a, b, c}, hashNode(hashNode(HashLeaf(a), HashLeaf(b)), HashLeaf(c))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Root(tt.leaves); !bytes.Equal(got, tt.want) {
+				t.Errorf("Root() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeafCannotPassAsNode(t *testing.T) {
+	a, b := HashLeaf([]byte("a")), HashLeaf([]byte("b"))
+	forged := append(append([]byte{}, a...), b...)
+	if bytes.Equal(HashLeaf(forged), hashNode(a, b)) {
+		t.Fatal("a leaf made of two hashes has the same hash as their inner node")
+	}
+}
+
+func TestProofVerifies(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 7, 8, 13} {
+		leaves := testLeaves(n)
+		root := Root(leaves)
+		for i := range leaves {
+			t.Run(fmt.Sprintf("%d leaves/index %d", n, i), func(t *testing.T) {
+				proof, err := Proof(leaves, i)
+				if err != nil {
+					t.Fatalf("Proof() error = %v", err)
+				}
+				if !Verify(leaves[i], proof, root) {
+					t.Errorf("Verify() = false for a valid proof")
+				}
+			})
+		}
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	leaves := testLeaves(5)
+	root := Root(leaves)
+	proof, err := Proof(leaves, 2)
+	if err != nil {
+		t.Fatalf("Proof() error = %v", err)
+	}
+
+	tampered := append([]ProofStep{}, proof...)
+	tampered[0].Left = !tampered[0].Left
+	badHash := append([]ProofStep{}, proof...)
+	badHash[0].Hash = "not hex"
+	otherSibling := append([]ProofStep{}, proof...)
+	otherSibling[0].Hash = hex.EncodeToString(HashLeaf([]byte("forged")))
+
+	tests := []struct {
+		name  string
+		leaf  []byte
+		proof []ProofStep
+		root  []byte
+	}{
+		{"other leaf", leaves[3], proof, root},
+		{"unknown leaf", []byte("forged"), proof, root},
+		{"flipped side", leaves[2], tampered, root},
+		{"invalid hash", leaves[2], badHash, root},
+		{"other sibling", leaves[2], otherSibling, root},
+		{"truncated proof", leaves[2], proof[:len(proof)-1], root},
+		{"other root", leaves[2], proof, Root(testLeaves(6))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Verify(tt.leaf, tt.proof, tt.root) {
+				t.Errorf("Verify() = true, want false")
+			}
+		})
+	}
+}
+
+func TestProofIndexOutOfRange(t *testing.T) {
+	leaves := testLeaves(3)
+	for _, index := range []int{-1, 3, 10} {
+		if _, err := Proof(leaves, index); err == nil {
+			t.Errorf("Proof(%d) error = nil, want out of range", index)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package receipt
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+)
+
+var signingKey ed25519.PrivateKey
+
+// Init loads the ed25519 signing key from RECEIPT_SIGNING_SEED (32 bytes hex). Receipts stay
+// verifiable across restarts as long as the seed does not change. There is no default seed:
+// anyone who knows it could sign receipts.
+func Init() error {
+	seed, err := hex.DecodeString(os.Getenv("RECEIPT_SIGNING_SEED"))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("RECEIPT_SIGNING_SEED must be set to %d bytes, hex encoded", ed25519.SeedSize)
+	}
+	signingKey = ed25519.NewKeyFromSeed(seed)
+	return nil
+}
+
+func key() ed25519.PrivateKey {
+	if signingKey == nil {
+		log.Fatal("receipt signing key not loaded, call receipt.Init at startup")
+	}
+	return signingKey
+}
+
+// Sign returns the base64 ed25519 signature of the payload.
+func Sign(payload []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key(), payload))
+}
+
+func Verify(payload []byte, signature string) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key().Public().(ed25519.PublicKey), payload, sig)
+}
+
+// PublicKey returns the base64 public key clients use to check receipts offline.
+func PublicKey() string {
+	return base64.StdEncoding.EncodeToString(key().Public().(ed25519.PublicKey))
+}
@@ -0,0 +1,80 @@
+package receipt
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const testSeed = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		seed    string
+		wantErr bool
+	}{
+		{"valid seed", testSeed, false},
+		{"missing", "", true},
+		{"not hex", strings.Repeat("zz", 32), true},
+		{"too short", testSeed[:62], true},
+		{"too long", testSeed + "20", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RECEIPT_SIGNING_SEED", tt.seed)
+			if err := Init(); (err != nil) != tt.wantErr {
+				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	t.Setenv("RECEIPT_SIGNING_SEED", testSeed)
+	if err := Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	payload := []byte(`{"voteId":"v1","ballotHash":"abc"}`)
+	signature := Sign(payload)
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+		want      bool
+	}{
+		{"valid", payload, signature, true},
+		{"changed payload", []byte(`{"voteId":"v2","ballotHash":"abc"}`), signature, false},
+		{"not base64", payload, "%%%", false},
+		{"truncated signature", payload, signature[:len(signature)-4], false},
+		{"empty signature", payload, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.payload, tt.signature); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicKeyChecksSignatures(t *testing.T) {
+	t.Setenv("RECEIPT_SIGNING_SEED", testSeed)
+	if err := Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(PublicKey())
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		t.Fatalf("PublicKey() is not a base64 ed25519 key: %v", err)
+	}
+
+	payload := []byte("receipt")
+	signature, _ := base64.StdEncoding.DecodeString(Sign(payload))
+	if !ed25519.Verify(publicKey, payload, signature) {
+		t.Error("signature does not verify with the published public key")
+	}
+}